@@ -0,0 +1,638 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	goParser "go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	strs "strings"
+	"text/template"
+
+	"github.com/wlMalk/goms/config"
+	"github.com/wlMalk/goms/generator"
+	"github.com/wlMalk/goms/generator/diff"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/importer"
+	"github.com/wlMalk/goms/lsp"
+	"github.com/wlMalk/goms/parser"
+	"github.com/wlMalk/goms/parser/types"
+	"github.com/wlMalk/goms/version"
+)
+
+type command struct {
+	name  string
+	short string
+	flags func(fs *flag.FlagSet) func() error
+}
+
+func commands() []*command {
+	return []*command{
+		{
+			name:  "generate",
+			short: "generate the service code from its definition (default command)",
+			flags: generateFlags,
+		},
+		{
+			name:  "init",
+			short: "create a skeleton service definition",
+			flags: initFlags,
+		},
+		{
+			name:  "import",
+			short: "create a service definition from a .proto file or an OpenAPI document",
+			flags: importFlags,
+		},
+		{
+			name:  "validate",
+			short: "parse and validate the service definition without generating code",
+			flags: validateFlags,
+		},
+		{
+			name:  "inspect",
+			short: "print the parsed service model as JSON",
+			flags: inspectFlags,
+		},
+		{
+			name:  "list-specs",
+			short: "list the names of the available file specs",
+			flags: listSpecsFlags,
+		},
+		{
+			name:  "watch",
+			short: "regenerate the service code whenever its definition changes",
+			flags: watchFlags,
+		},
+		{
+			name:  "lsp",
+			short: "run a language server for goms tags over stdio",
+			flags: lspFlags,
+		},
+		{
+			name:  "version",
+			short: "print the goms version",
+			flags: versionFlags,
+		},
+	}
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (cmd *command) flagSet() *flag.FlagSet {
+	fs, _ := cmd.setup()
+	return fs
+}
+
+func (cmd *command) setup() (*flag.FlagSet, func() error) {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	action := cmd.flags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: goms %s [flags]\n\n%s\n", cmd.name, strings.ToUpperFirst(cmd.short))
+		var hasFlags bool
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(fs.Output(), "\nFlags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs, action
+}
+
+func (cmd *command) run(args []string) error {
+	fs, action := cmd.setup()
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments for '%s': %s", cmd.name, strs.Join(fs.Args(), " "))
+	}
+	return action()
+}
+
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strs.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strs.Split(value, ",") {
+		if v = strs.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+type inputFlags struct {
+	in     listFlag
+	config string
+}
+
+func (f *inputFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.in, "in", "comma separated `list` of service definition files, or a package directory (default \".\")")
+	fs.StringVar(&f.config, "config", "", "`path` of the "+config.FileName+" file (default is the first one found from the service definition directory up to the module root)")
+}
+
+func (f *inputFlags) loadConfig(dir string) (*config.Config, error) {
+	p := f.config
+	if p == "" {
+		root, err := searchRoot(dir)
+		if err != nil {
+			return nil, err
+		}
+		if p = config.Find(dir, root); p == "" {
+			return &config.Config{}, nil
+		}
+	}
+	return config.Load(p)
+}
+
+func (f *inputFlags) files() (files []string, err error) {
+	in := f.in
+	if len(in) == 0 {
+		in = listFlag{"."}
+	}
+	for _, name := range in {
+		name, err = filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, name)
+			continue
+		}
+		if len(in) > 1 {
+			return nil, fmt.Errorf("'%s' is a directory, only files can be given when using more than one input", name)
+		}
+		matches, err := filepath.Glob(filepath.Join(name, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !strs.HasSuffix(match, "_test.go") {
+				files = append(files, match)
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no Go files were found in '%s'", name)
+		}
+	}
+	sort.Strings(files)
+	return
+}
+
+func (f *inputFlags) parse(opts ...parser.ParserOption) ([]types.Service, *config.Config, string, error) {
+	filePaths, err := f.files()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, filePath := range filePaths {
+		file, err := goParser.ParseFile(fset, relativePath(filePath), nil, goParser.ParseComments|goParser.AllErrors)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		files = append(files, file)
+	}
+	dir := filepath.Dir(filePaths[0])
+	cfg, err := f.loadConfig(dir)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	opts = append(append([]parser.ParserOption{parser.ResolveImportedEntities(dir), parser.FileSet(fset)}, cfg.ParserOptions()...), opts...)
+	services, err := parser.Default(opts...).ParseFiles(files...)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return services, cfg, dir, nil
+}
+
+type specsFlags struct {
+	include listFlag
+	exclude listFlag
+	plugins listFlag
+}
+
+func (f *specsFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.include, "specs", "comma separated `list` of the only specs to generate")
+	fs.Var(&f.exclude, "exclude-specs", "comma separated `list` of specs not to generate")
+	fs.Var(&f.plugins, "plugins", "comma separated `list` of plugins to run, either names of goms-gen-<name> executables on PATH or paths to executables")
+}
+
+func (f *specsFlags) generator(cfg *config.Config, extra ...generator.GeneratorOption) (*generator.Generator, error) {
+	available := generator.Default(extra...)
+	for _, name := range cfg.SpecNames() {
+		if !available.HasSpec(name) {
+			return nil, fmt.Errorf("unknown spec '%s' in '%s'", name, relativePath(cfg.Path))
+		}
+	}
+	for _, name := range append(append([]string{}, f.include...), f.exclude...) {
+		if !available.HasSpec(name) {
+			return nil, fmt.Errorf("unknown spec '%s'", name)
+		}
+	}
+	opts := append(append([]generator.GeneratorOption{}, extra...), cfg.GeneratorOptions()...)
+	if len(f.include) > 0 {
+		opts = append(opts, generator.IncludeSpecs(f.include...))
+	}
+	if len(f.exclude) > 0 {
+		opts = append(opts, generator.ExcludeSpecs(f.exclude...))
+	}
+	for _, name := range f.plugins {
+		opts = append(opts, generator.Plugin(name, ""))
+	}
+	return generator.Default(opts...), nil
+}
+
+func generateFlags(fs *flag.FlagSet) func() error {
+	var input inputFlags
+	var specs specsFlags
+	var out string
+	var dryRun, showDiff, check bool
+	input.register(fs)
+	fs.StringVar(&out, "out", "", "root `directory` for the versioned generated code (default is the directory of the service definition)")
+	specs.register(fs)
+	fs.BoolVar(&dryRun, "dry-run", false, "list the files that would be generated with their status without writing them")
+	fs.BoolVar(&showDiff, "diff", false, "print a unified diff between the files on disk and the generated ones without writing them")
+	fs.BoolVar(&check, "check", false, "exit with a non-zero status when generated files are stale without writing them")
+	return func() error {
+		if !showDiff {
+			banner()
+		}
+		changes, manifests, err := generate(&input, &specs, out)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			for _, change := range changes {
+				fmt.Printf("%-10s %s\n", change.Action, relativePath(change.Path))
+			}
+		}
+		if showDiff {
+			for _, change := range changes.Stale() {
+				from, to := "a/"+relativePath(change.Path), "b/"+relativePath(change.Path)
+				if change.Action == generator.FileCreate {
+					from = "/dev/null"
+				} else if change.Action == generator.FileDelete {
+					to = "/dev/null"
+				}
+				fmt.Print(diff.Unified(from, to, change.Existing, change.Content))
+			}
+		}
+		if check {
+			stale := changes.Stale()
+			if len(stale) > 0 {
+				if !dryRun {
+					for _, change := range stale {
+						fmt.Printf("%-10s %s\n", change.Action, relativePath(change.Path))
+					}
+				}
+				return fmt.Errorf("%d generated files are stale, run 'goms generate' to update them", len(stale))
+			}
+		}
+		if dryRun || showDiff || check {
+			return nil
+		}
+		err = changes.Save()
+		if err != nil {
+			return err
+		}
+		for _, manifest := range manifests {
+			err = manifest.Save()
+			if err != nil {
+				return err
+			}
+		}
+		success("All files are successfully generated")
+		return nil
+	}
+}
+
+// generate parses the service definition and generates the files of its
+// services, returning the changes to make on disk without writing them.
+func generate(input *inputFlags, specs *specsFlags, out string) (generator.FileChanges, []*generator.Manifest, error) {
+	services, cfg, dir, err := input.parse()
+	if err != nil {
+		return nil, nil, err
+	}
+	templates, err := templateSpecs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	g, err := specs.generator(cfg, templates...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if out == "" {
+		out = cfg.OutputDir()
+	}
+	if out != "" {
+		dir, err = filepath.Abs(out)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	importPath := cfg.ImportPath
+	if importPath == "" {
+		importPath, err = importPathOf(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	var changes generator.FileChanges
+	var manifests []*generator.Manifest
+	for _, service := range services {
+		versionDir := cfg.VersionDir(service.Version)
+		service.Path = filepath.Join(dir, filepath.FromSlash(versionDir))
+		service.ImportPath = path.Join(importPath, versionDir)
+		files, err := g.Generate(service)
+		if err != nil {
+			return nil, nil, err
+		}
+		serviceChanges, err := files.Changes()
+		if err != nil {
+			return nil, nil, err
+		}
+		manifest, err := generator.LoadManifest(service.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read manifest of '%s': %v", service.Path, err)
+		}
+		for _, p := range manifest.Edited(serviceChanges) {
+			warn(fmt.Sprintf("'%s' was edited by hand, the changes will be overwritten", relativePath(p)))
+		}
+		orphans, err := manifest.Orphans(serviceChanges, g.GeneratedSpecs())
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, orphan := range orphans {
+			if orphan.Action != generator.FileOrphan {
+				continue
+			}
+			if orphan.Overwrite {
+				warn(fmt.Sprintf("'%s' is not generated anymore but was edited by hand, so it is kept", relativePath(orphan.Path)))
+			} else {
+				warn(fmt.Sprintf("'%s' is not generated anymore, delete it if it is not needed", relativePath(orphan.Path)))
+			}
+		}
+		serviceChanges = append(serviceChanges, orphans...)
+		changes = append(changes, serviceChanges...)
+		manifests = append(manifests, generator.NewManifest(service.Path, serviceChanges, manifest, g.GeneratedSpecs()))
+	}
+	return changes, manifests, nil
+}
+
+func relativePath(p string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return p
+	}
+	rel, err := filepath.Rel(wd, p)
+	if err != nil || strs.HasPrefix(rel, "..") {
+		return p
+	}
+	return filepath.ToSlash(rel)
+}
+
+func validateFlags(fs *flag.FlagSet) func() error {
+	var input inputFlags
+	input.register(fs)
+	return func() error {
+		banner()
+		services, _, _, err := input.parse()
+		if err != nil {
+			return err
+		}
+		if len(services) == 0 {
+			return fmt.Errorf("no service interface was found")
+		}
+		for _, service := range services {
+			fmt.Printf("%s v%s: %d methods\n", service.Name, service.Version.String(), len(service.Methods))
+		}
+		success("Service definition is valid")
+		return nil
+	}
+}
+
+var serviceNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var serviceTemplate = template.Must(template.New("service").Parse(`package {{.Package}}
+
+import (
+	"context"
+)
+
+// Service is the interface describing the {{.Package}} service
+// @generate-all
+type {{.Interface}} interface {
+	// @http-method(GET)
+	// @params(name, (@http-origin(QUERY)))
+	Hello(ctx context.Context, name string) (greeting string, err error)
+}
+`))
+
+func initFlags(fs *flag.FlagSet) func() error {
+	var dir, name, ver string
+	fs.StringVar(&dir, "dir", ".", "`directory` to create service.go in")
+	fs.StringVar(&name, "name", "", "service `name`, used as the package name (default is the directory name)")
+	fs.StringVar(&ver, "version", "1.0", "service `version`")
+	return func() error {
+		banner()
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = strs.ToLower(strs.Replace(filepath.Base(dir), "-", "_", -1))
+		}
+		if !serviceNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid service name '%s'", name)
+		}
+		v, err := parser.ParseVersion(ver)
+		if err != nil {
+			return fmt.Errorf("invalid service version '%s': %v", ver, err)
+		}
+		filePath := filepath.Join(dir, "service.go")
+		if _, err := os.Stat(filePath); err == nil {
+			return fmt.Errorf("'%s' already exists", filePath)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		var b strs.Builder
+		err = serviceTemplate.Execute(&b, map[string]string{
+			"Package":   name,
+			"Interface": strings.ToUpperFirst(name) + "_v" + v.StringSpecial("_"),
+		})
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filePath, []byte(b.String()), 0644); err != nil {
+			return err
+		}
+		success(fmt.Sprintf("Service definition is created at '%s'", filePath))
+		return nil
+	}
+}
+
+func importFlags(fs *flag.FlagSet) func() error {
+	var from, dir, name, ver, service string
+	fs.StringVar(&from, "from", "", "`file` to import, a .proto file or an OpenAPI document (.yaml, .yml or .json)")
+	fs.StringVar(&dir, "dir", ".", "`directory` to create service.go in")
+	fs.StringVar(&name, "name", "", "service `name`, used as the package name (default is derived from the imported file)")
+	fs.StringVar(&ver, "version", "", "service `version` (default is derived from the imported file)")
+	fs.StringVar(&service, "service", "", "`name` of the service to import when the .proto file declares more than one")
+	return func() error {
+		banner()
+		if from == "" {
+			return fmt.Errorf("missing file to import, use -from")
+		}
+		s, err := importer.ImportFile(from, service)
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			if !serviceNameRegexp.MatchString(name) {
+				return fmt.Errorf("invalid service name '%s'", name)
+			}
+			s.Name = name
+		}
+		if ver != "" {
+			v, err := parser.ParseVersion(ver)
+			if err != nil {
+				return fmt.Errorf("invalid service version '%s': %v", ver, err)
+			}
+			s.Version = *v
+		}
+		if len(s.Methods) == 0 {
+			return fmt.Errorf("no methods were found in '%s'", from)
+		}
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		filePath := filepath.Join(dir, "service.go")
+		if _, err := os.Stat(filePath); err == nil {
+			return fmt.Errorf("'%s' already exists", filePath)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		src, err := importer.Render(*s)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filePath, src, 0644); err != nil {
+			return err
+		}
+		success(fmt.Sprintf("Service definition is imported into '%s' with %d methods", filePath, len(s.Methods)))
+		return nil
+	}
+}
+
+func inspectFlags(fs *flag.FlagSet) func() error {
+	var input inputFlags
+	input.register(fs)
+	var compact bool
+	fs.BoolVar(&compact, "compact", false, "print the JSON on a single line instead of indenting it")
+	return func() error {
+		services, _, _, err := input.parse()
+		if err != nil {
+			return err
+		}
+		model := struct {
+			GomsVersion string          `json:"gomsVersion"`
+			Services    []types.Service `json:"services"`
+		}{version.VERSION, services}
+		enc := json.NewEncoder(os.Stdout)
+		if !compact {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(model)
+	}
+}
+
+func searchRoot(dir string) (string, error) {
+	root, _, err := findModule(dir)
+	if err != nil {
+		return "", err
+	}
+	if root == "" {
+		return dir, nil
+	}
+	return root, nil
+}
+
+func templateSpecs(dir string) ([]generator.GeneratorOption, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	root, err := searchRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	templatesDir := generator.FindTemplatesDir(dir, root)
+	if templatesDir == "" {
+		return nil, nil
+	}
+	opt, err := generator.TemplateSpecs(templatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load templates from '%s': %v", relativePath(templatesDir), err)
+	}
+	return []generator.GeneratorOption{opt}, nil
+}
+
+func listSpecsFlags(fs *flag.FlagSet) func() error {
+	var dir string
+	fs.StringVar(&dir, "dir", ".", "`directory` to look for "+generator.TemplatesDir+" from")
+	return func() error {
+		templates, err := templateSpecs(dir)
+		if err != nil {
+			return err
+		}
+		for _, name := range generator.Default(templates...).Specs() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+}
+
+func lspFlags(fs *flag.FlagSet) func() error {
+	return func() error {
+		return lsp.Serve(os.Stdin, os.Stdout)
+	}
+}
+
+func versionFlags(fs *flag.FlagSet) func() error {
+	return func() error {
+		fmt.Printf("goms v%s\n", version.VERSION)
+		return nil
+	}
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	strs "strings"
+
+	"github.com/wlMalk/goms/generator"
+	"github.com/wlMalk/goms/parser"
+	"github.com/wlMalk/goms/parser/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+const FileName = "goms.yaml"
+
+const (
+	PolicyOverwrite = "overwrite"
+	PolicyKeep      = "keep"
+	PolicyMerge     = "merge"
+)
+
+type Config struct {
+	Path       string         `yaml:"-"`
+	Generate   []string       `yaml:"generate"`
+	Specs      SpecsConfig    `yaml:"specs"`
+	Output     OutputConfig   `yaml:"output"`
+	ImportPath string         `yaml:"importPath"`
+	Tags       TagsConfig     `yaml:"tags"`
+	Plugins    []PluginConfig `yaml:"plugins"`
+}
+
+type SpecsConfig struct {
+	Disable  []string          `yaml:"disable"`
+	Policies map[string]string `yaml:"policies"`
+}
+
+type OutputConfig struct {
+	Dir        string `yaml:"dir"`
+	VersionDir string `yaml:"versionDir"`
+}
+
+type TagsConfig struct {
+	Service []TagConfig `yaml:"service"`
+	Method  []TagConfig `yaml:"method"`
+	Param   []TagConfig `yaml:"param"`
+}
+
+type TagConfig struct {
+	Name string         `yaml:"name"`
+	Args []TagArgConfig `yaml:"args"`
+	Line int            `yaml:"-"`
+}
+
+type TagArgConfig struct {
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type"`
+	Required bool     `yaml:"required"`
+	Values   []string `yaml:"values"`
+}
+
+func (t *TagConfig) UnmarshalYAML(value *yaml.Node) error {
+	t.Line = value.Line
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&t.Name)
+	}
+	type tagConfig TagConfig
+	return value.Decode((*tagConfig)(t))
+}
+
+func (t TagConfig) schema() *parser.TagSchema {
+	if len(t.Args) == 0 {
+		return nil
+	}
+	schema := &parser.TagSchema{}
+	for _, arg := range t.Args {
+		typ := parser.TagArgType(arg.Type)
+		if typ == "" {
+			typ = parser.TagArgString
+		}
+		schema.Args = append(schema.Args, parser.TagArg{
+			Name:     arg.Name,
+			Type:     typ,
+			Required: arg.Required,
+			Values:   arg.Values,
+		})
+	}
+	return schema
+}
+
+type PluginConfig struct {
+	Name      string `yaml:"name"`
+	Parameter string `yaml:"parameter"`
+}
+
+func Find(dir string, root string) string {
+	dir, root = filepath.Clean(dir), filepath.Clean(root)
+	for {
+		p := filepath.Join(dir, FileName)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("cannot read config '%s': %v", path, err)
+	}
+	c.Path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config '%s': %v", path, err)
+	}
+	return c, nil
+}
+
+func (c *Config) validate() error {
+	for name, policy := range c.Specs.Policies {
+		switch policy {
+		case PolicyOverwrite, PolicyKeep, PolicyMerge:
+		default:
+			return fmt.Errorf("invalid policy '%s' for spec '%s', expected one of '%s', '%s' or '%s'", policy, name, PolicyOverwrite, PolicyKeep, PolicyMerge)
+		}
+	}
+	for _, plugin := range c.Plugins {
+		if plugin.Name == "" {
+			return fmt.Errorf("plugin without a name")
+		}
+	}
+	return c.Tags.register(parser.Default())
+}
+
+func (t TagsConfig) register(p *parser.Parser) error {
+	for _, tag := range t.Service {
+		var err error
+		if schema := tag.schema(); schema != nil {
+			err = p.RegisterServiceTagSchema(tag.Name, *schema)
+		} else {
+			err = p.RegisterServiceTagParser(tag.Name, serviceValueTag)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %v", tag.Line, err)
+		}
+	}
+	for _, tag := range t.Method {
+		var err error
+		if schema := tag.schema(); schema != nil {
+			err = p.RegisterMethodTagSchema(tag.Name, *schema)
+		} else {
+			err = p.RegisterMethodTagParser(tag.Name, methodValueTag)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %v", tag.Line, err)
+		}
+	}
+	for _, tag := range t.Param {
+		var err error
+		if schema := tag.schema(); schema != nil {
+			err = p.RegisterParamTagSchema(tag.Name, *schema)
+		} else {
+			err = p.RegisterParamTagParser(tag.Name, paramValueTag)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %v", tag.Line, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) SpecNames() (names []string) {
+	names = append(names, c.Specs.Disable...)
+	for name := range c.Specs.Policies {
+		names = append(names, name)
+	}
+	return
+}
+
+func (c *Config) ParserOptions() (opts []parser.ParserOption) {
+	if len(c.Generate) > 0 {
+		opts = append(opts, parser.DefaultServiceGenerateFlags(c.Generate...))
+	}
+	tags := c.Tags
+	opts = append(opts, func(p *parser.Parser) {
+		// the tags were checked when loading the config
+		tags.register(p)
+	})
+	return
+}
+
+func (c *Config) GeneratorOptions() (opts []generator.GeneratorOption) {
+	if len(c.Specs.Disable) > 0 {
+		opts = append(opts, generator.ExcludeSpecs(c.Specs.Disable...))
+	}
+	for name, policy := range c.Specs.Policies {
+		opts = append(opts, generator.SpecPolicy(name, policy == PolicyOverwrite, policy == PolicyMerge))
+	}
+	for _, plugin := range c.Plugins {
+		opts = append(opts, generator.Plugin(plugin.Name, plugin.Parameter))
+	}
+	return
+}
+
+// OutputDir returns the root directory of the generated code, relative paths
+// being resolved from the directory of the config file.
+func (c *Config) OutputDir() string {
+	if c.Output.Dir == "" || filepath.IsAbs(c.Output.Dir) {
+		return c.Output.Dir
+	}
+	return filepath.Join(filepath.Dir(c.Path), filepath.FromSlash(c.Output.Dir))
+}
+
+func (c *Config) VersionDir(version types.Version) string {
+	return VersionDir(c.Output.VersionDir, version)
+}
+
+func VersionDir(format string, version types.Version) string {
+	if format == "" {
+		return "v" + version.FullStringSpecial(".")
+	}
+	return strs.NewReplacer(
+		"{major}", strconv.Itoa(version.Major),
+		"{minor}", strconv.Itoa(version.Minor),
+		"{patch}", strconv.Itoa(version.Patch),
+	).Replace(format)
+}
+
+func serviceValueTag(service types.Service, options types.TagOptions, tag string) error {
+	return valueTag(options, tag)
+}
+
+func methodValueTag(method types.Method, options types.TagOptions, tag string) error {
+	return valueTag(options, tag)
+}
+
+func paramValueTag(arg types.Argument, options types.TagOptions, tag string) error {
+	return valueTag(options, tag)
+}
+
+func valueTag(options types.TagOptions, tag string) error {
+	if tag != "" {
+		options["value"] = tag
+	}
+	return nil
+}
@@ -20,14 +20,29 @@ const (
 	ServiceGeneratorHTTPTransportClientStruct                 string = "http-transport-client-struct"
 	ServiceGeneratorHTTPTransportServerRegisterFunc           string = "http-transport-server-register-func"
 	ServiceGeneratorHTTPTransportServerRegisterSpecialFunc    string = "http-transport-server-register-special-func"
+	ServiceGeneratorJSONRPCTransportClientGlobalVar           string = "jsonrpc-transport-client-global-var"
+	ServiceGeneratorJSONRPCTransportClientNewFunc             string = "jsonrpc-transport-client-new-func"
+	ServiceGeneratorJSONRPCTransportClientNewSpecialFunc      string = "jsonrpc-transport-client-new-special-func"
+	ServiceGeneratorJSONRPCTransportClientStruct              string = "jsonrpc-transport-client-struct"
+	ServiceGeneratorJSONRPCTransportServerRegisterFunc        string = "jsonrpc-transport-server-register-func"
+	ServiceGeneratorJSONRPCTransportServerRegisterSpecialFunc string = "jsonrpc-transport-server-register-special-func"
 	ServiceGeneratorHandlerConverterNewFuncs                  string = "handler-converter-new-funcs"
 	ServiceGeneratorHandlerConverterTypes                     string = "handler-converter-types"
 	ServiceGeneratorLocalClientGlobalVar                      string = "local-client-global-var"
 	ServiceGeneratorLoggingMiddlewareNewFunc                  string = "logging-middleware-new-func"
 	ServiceGeneratorLoggingMiddlewareStructs                  string = "logging-middleware-structs"
 	ServiceGeneratorLoggingMiddlewareTypes                    string = "logging-middleware-types"
+	ServiceGeneratorMQTransportClientGlobalVar                string = "mq-transport-client-global-var"
+	ServiceGeneratorMQTransportClientNewFunc                  string = "mq-transport-client-new-func"
+	ServiceGeneratorMQTransportClientNewSpecialFunc           string = "mq-transport-client-new-special-func"
+	ServiceGeneratorMQTransportClientStruct                   string = "mq-transport-client-struct"
+	ServiceGeneratorMQTransportServerRegisterFunc             string = "mq-transport-server-register-func"
+	ServiceGeneratorMQTransportServerRegisterSpecialFunc      string = "mq-transport-server-register-special-func"
+	ServiceGeneratorOpenAPIDocument                           string = "open-api-document"
 	ServiceGeneratorProtoBufPackageDefinition                 string = "proto-buf-package-definition"
 	ServiceGeneratorProtoBufServiceDefinition                 string = "proto-buf-service-definition"
+	ServiceGeneratorPublishingMiddlewareNewFunc               string = "publishing-middleware-new-func"
+	ServiceGeneratorPublishingMiddlewareStruct                string = "publishing-middleware-struct"
 	ServiceGeneratorRecoveringMiddlewareNewFunc               string = "recovering-middleware-new-func"
 	ServiceGeneratorRecoveringMiddlewareStruct                string = "recovering-middleware-struct"
 	ServiceGeneratorServiceApplyMiddlewareConditionalFunc     string = "service-apply-middleware-conditional-func"
@@ -48,6 +63,7 @@ const (
 	ServiceGeneratorServiceMainPrepareEndpointsFunc           string = "service-main-prepare-endpoints-func"
 	ServiceGeneratorServiceMainServeGRPCFunc                  string = "service-main-serve-grpc-func"
 	ServiceGeneratorServiceMainServeHTTPFunc                  string = "service-main-serve-http-func"
+	ServiceGeneratorServiceMainServeJSONRPCFunc               string = "service-main-serve-jsonrpc-func"
 	ServiceGeneratorServiceMiddlewareChainFunc                string = "service-middleware-chain-func"
 	ServiceGeneratorServiceMiddlewareTypes                    string = "service-middleware-types"
 	ServiceGeneratorServiceRequestResponseMiddlewareChainFunc string = "service-request-response-middleware-chain-func"
@@ -56,6 +72,7 @@ const (
 	ServiceGeneratorServiceStructTypeNewFunc                  string = "service-struct-type-new-func"
 	ServiceGeneratorValidatingMiddlewareNewFunc               string = "validating-middleware-new-func"
 	ServiceGeneratorValidatingMiddlewareStruct                string = "validating-middleware-struct"
+	ServiceGeneratorValidatingRegexps                         string = "validating-regexps"
 	ServiceGeneratorValidatingValidatorsTypes                 string = "validating-validators-types"
 )
 
@@ -70,6 +87,7 @@ const (
 	MethodGeneratorGRPCResponseEncoder                        string = "grpc-response-encoder"
 	MethodGeneratorGRPCTransportClientGlobalFunc              string = "grpc-transport-client-global-func"
 	MethodGeneratorGRPCTransportClientMethodFunc              string = "grpc-transport-client-method-func"
+	MethodGeneratorGRPCTransportClientStreamEndpointFunc      string = "grpc-transport-client-stream-endpoint-func"
 	MethodGeneratorGRPCTransportServerHandlerMethodFunc       string = "grpc-transport-server-handler-method-func"
 	MethodGeneratorHTTPRequest                                string = "http-request"
 	MethodGeneratorHTTPRequestDecoder                         string = "http-request-decoder"
@@ -87,15 +105,33 @@ const (
 	MethodGeneratorHTTPTransportClientGlobalFunc              string = "http-transport-client-global-func"
 	MethodGeneratorHTTPTransportClientMethodFunc              string = "http-transport-client-method-func"
 	MethodGeneratorHandlerToRequestResponseHandlerConverter   string = "handler-to-request-response-handler-converter"
+	MethodGeneratorJSONRPCParams                              string = "jsonrpc-params"
+	MethodGeneratorJSONRPCRequestDecoder                      string = "jsonrpc-request-decoder"
+	MethodGeneratorJSONRPCRequestEncoder                      string = "jsonrpc-request-encoder"
+	MethodGeneratorJSONRPCResponseDecoder                     string = "jsonrpc-response-decoder"
+	MethodGeneratorJSONRPCResponseEncoder                     string = "jsonrpc-response-encoder"
+	MethodGeneratorJSONRPCResult                              string = "jsonrpc-result"
+	MethodGeneratorJSONRPCTransportClientGlobalFunc           string = "jsonrpc-transport-client-global-func"
+	MethodGeneratorJSONRPCTransportClientMethodFunc           string = "jsonrpc-transport-client-method-func"
 	MethodGeneratorLocalClientGlobalFunc                      string = "local-client-global-func"
 	MethodGeneratorLoggingMiddlewareMethodHandler             string = "logging-middleware-method-handler"
+	MethodGeneratorMQRequest                                  string = "mq-request"
+	MethodGeneratorMQRequestDecoder                           string = "mq-request-decoder"
+	MethodGeneratorMQRequestEncoder                           string = "mq-request-encoder"
+	MethodGeneratorMQResponse                                 string = "mq-response"
+	MethodGeneratorMQResponseDecoder                          string = "mq-response-decoder"
+	MethodGeneratorMQResponseEncoder                          string = "mq-response-encoder"
+	MethodGeneratorMQTransportClientGlobalFunc                string = "mq-transport-client-global-func"
+	MethodGeneratorMQTransportClientMethodFunc                string = "mq-transport-client-method-func"
 	MethodGeneratorMethodHandlers                             string = "method-handlers"
+	MethodGeneratorOpenAPIMethodSchemas                       string = "open-api-method-schemas"
 	MethodGeneratorProtoBufMethodRequestDefinition            string = "proto-buf-method-request-definition"
 	MethodGeneratorProtoBufMethodResponseDefinition           string = "proto-buf-method-response-definition"
 	MethodGeneratorProtoRequestNewFunc                        string = "proto-request-new-func"
 	MethodGeneratorProtoRequestNewProtoFunc                   string = "proto-request-new-proto-func"
 	MethodGeneratorProtoResponseNewFunc                       string = "proto-response-new-func"
 	MethodGeneratorProtoResponseNewProtoFunc                  string = "proto-response-new-proto-func"
+	MethodGeneratorPublishingMiddlewareMethodFunc             string = "publishing-middleware-method-func"
 	MethodGeneratorRecoveringMiddlewareMethodFunc             string = "recovering-middleware-method-func"
 	MethodGeneratorRequestResponseHandlerToEndpointConverter  string = "request-response-handler-to-endpoint-converter"
 	MethodGeneratorRequestResponseHandlerToHandlerConverter   string = "request-response-handler-to-handler-converter"
@@ -112,16 +148,21 @@ const (
 )
 
 const (
+	EntityGeneratorOpenAPIEntitySchema      string = "open-api-entity-schema"
 	EntityGeneratorProtoBufEntityDefinition string = "proto-buf-entity-definition"
+	EntityGeneratorProtoEntityNewFunc       string = "proto-entity-new-func"
+	EntityGeneratorProtoEntityNewProtoFunc  string = "proto-entity-new-proto-func"
 	EntityGeneratorServiceEntityType        string = "service-entity-type"
 )
 
 const (
+	ArgumentsGroupGeneratorOpenAPIArgumentsGroupSchema      string = "open-api-arguments-group-schema"
 	ArgumentsGroupGeneratorProtoBufArgumentsGroupDefinition string = "proto-buf-arguments-group-definition"
 	ArgumentsGroupGeneratorServiceArgumentsGroupType        string = "service-arguments-group-type"
 )
 
 const (
+	EnumGeneratorOpenAPIEnumSchema      string = "open-api-enum-schema"
 	EnumGeneratorProtoBufEnumDefinition string = "proto-buf-enum-definition"
 	EnumGeneratorServiceEnumType        string = "service-enum-type"
 )
@@ -137,18 +178,33 @@ const (
 	SpecNameGRPCServer                      string = "grpc-server"
 	SpecNameGlobalGRPCClient                string = "global-grpc-client"
 	SpecNameGlobalHTTPClient                string = "global-http-client"
+	SpecNameGlobalJSONRPCClient             string = "global-jsonrpc-client"
 	SpecNameGlobalLocalClient               string = "global-local-client"
+	SpecNameGlobalMQClient                  string = "global-mq-client"
 	SpecNameHTTPClient                      string = "http-client"
 	SpecNameHTTPDecoders                    string = "http-decoders"
 	SpecNameHTTPEncoders                    string = "http-encoders"
 	SpecNameHTTPRequests                    string = "http-requests"
 	SpecNameHTTPResponses                   string = "http-responses"
 	SpecNameHTTPServer                      string = "http-server"
+	SpecNameJSONRPCClient                   string = "jsonrpc-client"
+	SpecNameJSONRPCDecoders                 string = "jsonrpc-decoders"
+	SpecNameJSONRPCEncoders                 string = "jsonrpc-encoders"
+	SpecNameJSONRPCMessages                 string = "jsonrpc-messages"
+	SpecNameJSONRPCServer                   string = "jsonrpc-server"
 	SpecNameHandlers                        string = "handlers"
 	SpecNameLoggingMiddleware               string = "logging-middleware"
+	SpecNameMQClient                        string = "mq-client"
+	SpecNameMQDecoders                      string = "mq-decoders"
+	SpecNameMQEncoders                      string = "mq-encoders"
+	SpecNameMQMessages                      string = "mq-messages"
+	SpecNameMQServer                        string = "mq-server"
+	SpecNameOpenAPI                         string = "open-api"
 	SpecNameProtoBufServiceDefinitions      string = "proto-buf-service-definitions"
+	SpecNameProtoEntitiesConverters         string = "proto-entities-converters"
 	SpecNameProtoRequestsConverters         string = "proto-requests-converters"
 	SpecNameProtoResponsesConverters        string = "proto-responses-converters"
+	SpecNamePublishingMiddleware            string = "publishing-middleware"
 	SpecNameRecoveringMiddleware            string = "recovering-middleware"
 	SpecNameRequests                        string = "requests"
 	SpecNameResponses                       string = "responses"
@@ -173,12 +229,17 @@ const (
 	ServiceGenerateGRPCServerFlag       string = "grpc-server"
 	ServiceGenerateHTTPClientFlag       string = "http-client"
 	ServiceGenerateHTTPServerFlag       string = "http-server"
+	ServiceGenerateJSONRPCClientFlag    string = "jsonrpc-client"
+	ServiceGenerateJSONRPCServerFlag    string = "jsonrpc-server"
 	ServiceGenerateLatencyMetricFlag    string = "latency-metric"
 	ServiceGenerateLoggerFlag           string = "logger"
 	ServiceGenerateLoggingFlag          string = "logging"
+	ServiceGenerateMQClientFlag         string = "mq-client"
+	ServiceGenerateMQServerFlag         string = "mq-server"
 	ServiceGenerateMainFlag             string = "main"
 	ServiceGenerateMethodStubsFlag      string = "method-stubs"
 	ServiceGenerateMiddlewareFlag       string = "middleware"
+	ServiceGenerateOpenAPIFlag          string = "open-api"
 	ServiceGenerateProtoBufFlag         string = "proto-buf"
 	ServiceGenerateRateLimitingFlag     string = "rate-limiting"
 	ServiceGenerateRecoveringFlag       string = "recovering"
@@ -197,8 +258,12 @@ const (
 	MethodGenerateGRPCServerFlag      string = "grpc-server"
 	MethodGenerateHTTPClientFlag      string = "http-client"
 	MethodGenerateHTTPServerFlag      string = "http-server"
+	MethodGenerateJSONRPCClientFlag   string = "jsonrpc-client"
+	MethodGenerateJSONRPCServerFlag   string = "jsonrpc-server"
 	MethodGenerateLatencyMetricFlag   string = "latency-metric"
 	MethodGenerateLoggingFlag         string = "logging"
+	MethodGenerateMQClientFlag        string = "mq-client"
+	MethodGenerateMQServerFlag        string = "mq-server"
 	MethodGenerateMethodStubsFlag     string = "method-stubs"
 	MethodGenerateMiddlewareFlag      string = "middleware"
 	MethodGenerateRateLimitingFlag    string = "rate-limiting"
@@ -211,11 +276,25 @@ const (
 const (
 	ServiceGenerateGroupGRPC    string = "grpc"
 	ServiceGenerateGroupHTTP    string = "http"
+	ServiceGenerateGroupJSONRPC string = "jsonrpc"
 	ServiceGenerateGroupMetrics string = "metrics"
+	ServiceGenerateGroupMQ      string = "mq"
 )
 
 const (
 	MethodGenerateGroupGRPC    string = "grpc"
 	MethodGenerateGroupHTTP    string = "http"
+	MethodGenerateGroupJSONRPC string = "jsonrpc"
 	MethodGenerateGroupMetrics string = "metrics"
+	MethodGenerateGroupMQ      string = "mq"
+)
+
+const (
+	ValidatorEnum     string = "enum"
+	ValidatorMaxLen   string = "max-len"
+	ValidatorMinLen   string = "min-len"
+	ValidatorNonEmpty string = "non-empty"
+	ValidatorRange    string = "range"
+	ValidatorRegex    string = "regex"
+	ValidatorRequired string = "required"
 )
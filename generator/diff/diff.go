@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	strs "strings"
+)
+
+const context = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+func Unified(fromName string, toName string, from []byte, to []byte) string {
+	a, b := splitLines(from), splitLines(to)
+	ops := diffLines(a, b)
+	changed := false
+	for _, o := range ops {
+		if o.kind != opEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "--- %s\n+++ %s\n", fromName, toName)
+	aLine, bLine := 1, 1
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				end += context
+				if end > len(ops) {
+					end = len(ops)
+				}
+				break
+			}
+			end = next
+		}
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		var aCount, bCount int
+		var lines []string
+		for _, o := range ops[start:end] {
+			switch o.kind {
+			case opEqual:
+				aCount++
+				bCount++
+				lines = append(lines, " "+o.line)
+			case opDelete:
+				aCount++
+				lines = append(lines, "-"+o.line)
+			case opInsert:
+				bCount++
+				lines = append(lines, "+"+o.line)
+			}
+		}
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, l := range lines {
+			fmt.Fprintln(buf, l)
+		}
+		for _, o := range ops[i:end] {
+			if o.kind != opInsert {
+				aLine++
+			}
+			if o.kind != opDelete {
+				bLine++
+			}
+		}
+		i = end
+	}
+	return buf.String()
+}
+
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	return strs.Split(strs.TrimSuffix(string(b), "\n"), "\n")
+}
+
+func diffLines(a []string, b []string) (ops []op) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for _, l := range a[:prefix] {
+		ops = append(ops, op{kind: opEqual, line: l})
+	}
+	ops = append(ops, lcs(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, op{kind: opEqual, line: l})
+	}
+	return
+}
+
+func lcs(a []string, b []string) (ops []op) {
+	n, m := len(a), len(b)
+	lengths := make([][]int, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		if a[i] == b[j] {
+			ops = append(ops, op{kind: opEqual, line: a[i]})
+			i++
+			j++
+		} else if lengths[i+1][j] >= lengths[i][j+1] {
+			ops = append(ops, op{kind: opDelete, line: a[i]})
+			i++
+		} else {
+			ops = append(ops, op{kind: opInsert, line: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, op{kind: opDelete, line: a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, op{kind: opInsert, line: b[j]})
+	}
+	return
+}
@@ -3,9 +3,11 @@ package generator
 import (
 	"bytes"
 	"fmt"
-	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/wlMalk/goms/generator/file"
@@ -40,73 +42,191 @@ func generateFileHeader(overwrite bool) (lines []string) {
 	return
 }
 
-type Files []file.File
+type FileAction string
 
-func (fs Files) Save() error {
-	for _, f := range fs {
-		if f.IsEmpty() {
+const (
+	FileCreate    FileAction = "create"
+	FileOverwrite FileAction = "overwrite"
+	FileMerge     FileAction = "merge"
+	FileSkip      FileAction = "skip"
+	FileUnchanged FileAction = "unchanged"
+	FileDelete    FileAction = "delete"
+	FileOrphan    FileAction = "orphan"
+)
+
+type FileChange struct {
+	Path      string
+	Spec      string
+	Action    FileAction
+	Overwrite bool
+	Existing  []byte
+	Content   []byte
+}
+
+func (c FileChange) IsStale() bool {
+	return c.Action == FileCreate || c.Action == FileOverwrite || c.Action == FileMerge || c.Action == FileDelete
+}
+
+type FileChanges []FileChange
+
+func (cs FileChanges) Stale() (stale FileChanges) {
+	for _, c := range cs {
+		if c.IsStale() {
+			stale = append(stale, c)
+		}
+	}
+	return
+}
+
+func (cs FileChanges) Save() error {
+	for _, c := range cs {
+		if !c.IsStale() {
 			continue
 		}
-		fileDir := filepath.Join(f.Base(), f.Path())
-		if _, err := os.Stat(fileDir); err != nil {
-			if os.IsNotExist(err) {
-				err = os.MkdirAll(fileDir, 0700)
-				if err != nil {
-					return err
-				}
-			} else {
+		if c.Action == FileDelete {
+			err := removeFile(c.Path)
+			if err != nil {
 				return err
 			}
+			continue
 		}
-		fileName := ""
-		if f.Extension() != "" {
-			fileName = f.Name() + "." + f.Extension()
-		} else {
-			fileName = f.Name()
+		err := os.MkdirAll(filepath.Dir(c.Path), 0700)
+		if err != nil {
+			return err
 		}
-		filePath := filepath.Join(fileDir, fileName)
-		_, err := os.Stat(filePath)
-		if (err != nil && os.IsNotExist(err)) || (err == nil && f.Overwrite()) {
-			buf := new(bytes.Buffer)
-			header := f.FormatComments(generateFileHeader(f.Overwrite())...)
-			for _, h := range header {
-				fmt.Fprintln(buf, h)
-			}
-			_, err = f.WriteTo(buf)
-			if err != nil {
-				return err
-			}
-			file, err := os.Create(filePath)
-			if err != nil && os.IsExist(err) {
-				err = os.Remove(filePath)
-				if err != nil {
-					return err
-				}
-				file, err = os.Create(filePath)
-				if err != nil {
-					return err
-				}
-			} else if err != nil {
-				return err
-			}
-			defer func() {
-				err := file.Close()
-				if err != nil {
-					panic(err)
-				}
-			}()
-			_, err = io.Copy(file, buf)
-			if err != nil {
-				return err
-			}
-			// } else if err == nil && f.Merge() {
-			// TODO
-		} else if err != nil {
+		err = writeFile(c.Path, c.Content)
+		if err != nil {
 			return err
 		}
-		// } else if !f.Overwrite() {
-		// 	continue
-		// }
 	}
 	return nil
 }
+
+// SpecFile is a file along with the name of the spec it was generated by.
+type SpecFile struct {
+	Spec string
+	file.File
+}
+
+type Files []SpecFile
+
+func (fs Files) Save() error {
+	changes, err := fs.Changes()
+	if err != nil {
+		return err
+	}
+	return changes.Save()
+}
+
+func (fs Files) Changes() (changes FileChanges, err error) {
+	for _, f := range fs {
+		if f.IsEmpty() {
+			continue
+		}
+		change, err := fileChange(f.File)
+		if err != nil {
+			return nil, err
+		}
+		change.Spec = f.Spec
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return
+}
+
+func fileChange(f file.File) (change FileChange, err error) {
+	change.Path = filepath.Join(f.Base(), f.Path(), fileName(f))
+	change.Overwrite = f.Overwrite()
+	change.Existing, err = ioutil.ReadFile(change.Path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return change, err
+	}
+	err = nil
+	switch {
+	case !exists || f.Overwrite():
+		buf := new(bytes.Buffer)
+		header := f.FormatComments(generateFileHeader(f.Overwrite())...)
+		for _, h := range header {
+			fmt.Fprintln(buf, h)
+		}
+		_, err = f.WriteTo(buf)
+		if err != nil {
+			return change, err
+		}
+		change.Content = buf.Bytes()
+		if !exists {
+			change.Action = FileCreate
+		} else if bytes.Equal(withoutGenerationTime(change.Existing), withoutGenerationTime(change.Content)) {
+			change.Action = FileUnchanged
+		} else {
+			change.Action = FileOverwrite
+		}
+	case f.Merge():
+		merger, ok := f.(file.Merger)
+		if !ok {
+			change.Action = FileSkip
+			return
+		}
+		buf := new(bytes.Buffer)
+		_, err = merger.MergeTo(buf, change.Existing)
+		if err != nil {
+			return change, fmt.Errorf("cannot merge generated code into '%s': %v", change.Path, err)
+		}
+		change.Content = buf.Bytes()
+		if bytes.Equal(change.Existing, change.Content) {
+			change.Action = FileUnchanged
+		} else {
+			change.Action = FileMerge
+		}
+	default:
+		change.Action = FileSkip
+	}
+	return
+}
+
+var generationTimeRegexp = regexp.MustCompile(`(?m)^.*Generated At: .*\n`)
+
+func withoutGenerationTime(b []byte) []byte {
+	if loc := generationTimeRegexp.FindIndex(b); loc != nil {
+		return append(append([]byte{}, b[:loc[0]]...), b[loc[1]:]...)
+	}
+	return b
+}
+
+func fileName(f file.File) string {
+	if f.Extension() != "" {
+		return f.Name() + "." + f.Extension()
+	}
+	return f.Name()
+}
+
+func writeFile(filePath string, b []byte) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(b)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func removeFile(filePath string) error {
+	err := os.Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for dir := filepath.Dir(filePath); ; dir = filepath.Dir(dir) {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return nil
+		}
+		if os.Remove(dir) != nil {
+			return nil
+		}
+	}
+}
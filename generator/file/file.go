@@ -25,3 +25,7 @@ type File interface {
 	Ps(s ...string)
 	Pf(format string, args ...interface{})
 }
+
+type Merger interface {
+	MergeTo(w io.Writer, existing []byte) (n int64, err error)
+}
@@ -68,6 +68,7 @@ type Spec struct {
 	argumentsGroupGenerators map[string]argumentsGroupGeneratorHandler
 	entityGenerators         map[string]entityGeneratorHandler
 	enumGenerators           map[string]enumGeneratorHandler
+	order                    []string
 	conditions               []ServiceCondition
 	overwrite                bool
 	overwriteFunc            func(service types.Service) bool
@@ -133,6 +134,7 @@ func (f Spec) AddServiceGenerator(name string, generator ServiceGenerator, conds
 		conditions: conds,
 	}
 	f.serviceGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -141,6 +143,7 @@ func (f Spec) ServiceGenerator(name string, generator ServiceGenerator) Spec {
 	g := f.getServiceGenerator(name)
 	g.generator = generator
 	f.serviceGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -149,6 +152,7 @@ func (f Spec) ServiceGeneratorConditions(name string, conds ...ServiceCondition)
 	g := f.getServiceGenerator(name)
 	g.conditions = append(g.conditions, conds...)
 	f.serviceGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -159,6 +163,7 @@ func (f Spec) AddArgumentsGroupGenerator(name string, generator ArgumentsGroupGe
 		conditions: conds,
 	}
 	f.argumentsGroupGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -167,6 +172,7 @@ func (f Spec) ArgumentsGroupGenerator(name string, generator ArgumentsGroupGener
 	g := f.getArgumentsGroupGenerator(name)
 	g.generator = generator
 	f.argumentsGroupGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -175,6 +181,7 @@ func (f Spec) ArgumentsGroupGeneratorConditions(name string, conds ...ArgumentsG
 	g := f.getArgumentsGroupGenerator(name)
 	g.conditions = append(g.conditions, conds...)
 	f.argumentsGroupGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -185,6 +192,7 @@ func (f Spec) AddEntityGenerator(name string, generator EntityGenerator, conds .
 		conditions: conds,
 	}
 	f.entityGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -193,6 +201,7 @@ func (f Spec) EntityGenerator(name string, generator EntityGenerator) Spec {
 	g := f.getEntityGenerator(name)
 	g.generator = generator
 	f.entityGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -201,6 +210,7 @@ func (f Spec) EntityGeneratorConditions(name string, conds ...EntityCondition) S
 	g := f.getEntityGenerator(name)
 	g.conditions = append(g.conditions, conds...)
 	f.entityGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -211,6 +221,7 @@ func (f Spec) AddEnumGenerator(name string, generator EnumGenerator, conds ...En
 		conditions: conds,
 	}
 	f.enumGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -219,6 +230,7 @@ func (f Spec) EnumGenerator(name string, generator EnumGenerator) Spec {
 	g := f.getEnumGenerator(name)
 	g.generator = generator
 	f.enumGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -227,6 +239,7 @@ func (f Spec) EnumGeneratorConditions(name string, conds ...EnumCondition) Spec
 	g := f.getEnumGenerator(name)
 	g.conditions = append(g.conditions, conds...)
 	f.enumGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -238,6 +251,7 @@ func (f Spec) AddMethodGenerator(name string, generator MethodGenerator, extract
 		conditions: conds,
 	}
 	f.methodGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -246,6 +260,7 @@ func (f Spec) MethodGenerator(name string, generator MethodGenerator) Spec {
 	g := f.getMethodGenerator(name)
 	g.generator = generator
 	f.methodGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -254,6 +269,7 @@ func (f Spec) MethodGeneratorConditions(name string, conds ...MethodCondition) S
 	g := f.getMethodGenerator(name)
 	g.conditions = append(g.conditions, conds...)
 	f.methodGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -262,6 +278,7 @@ func (f Spec) MethodGeneratorExtractor(name string, extractor MethodsExtractor)
 	g := f.getMethodGenerator(name)
 	g.extractor = extractor
 	f.methodGenerators[name] = g
+	f.order = appendName(f.order, name)
 	return f
 }
 
@@ -271,6 +288,15 @@ func (f Spec) RemoveGenerator(name string) Spec {
 	return f
 }
 
+func appendName(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names[:len(names):len(names)], name)
+}
+
 func (f Spec) getServiceGenerator(name string) serviceGeneratorHandler {
 	if h, ok := f.serviceGenerators[name]; ok {
 		return h
@@ -350,7 +376,11 @@ func (f Spec) Generate(service types.Service, creator Creator) (File, error) {
 }
 
 func (f Spec) generateService(service types.Service, file File) (err error) {
-	for _, g := range f.serviceGenerators {
+	for _, name := range f.order {
+		g, ok := f.serviceGenerators[name]
+		if !ok {
+			continue
+		}
 		if !checkServiceConditions(service, g.conditions...) {
 			continue
 		}
@@ -363,7 +393,11 @@ func (f Spec) generateService(service types.Service, file File) (err error) {
 }
 
 func (f Spec) generateMethods(service types.Service, file File) (err error) {
-	for _, g := range f.methodGenerators {
+	for _, name := range f.order {
+		g, ok := f.methodGenerators[name]
+		if !ok {
+			continue
+		}
 		methods := service.Methods
 		if g.extractor != nil {
 			methods = g.extractor(service)
@@ -382,7 +416,11 @@ func (f Spec) generateMethods(service types.Service, file File) (err error) {
 }
 
 func (f Spec) generateEntities(service types.Service, file File) (err error) {
-	for _, g := range f.entityGenerators {
+	for _, name := range f.order {
+		g, ok := f.entityGenerators[name]
+		if !ok {
+			continue
+		}
 		entities := service.Entities
 		for _, entity := range entities {
 			if !checkEntityConditions(service, entity, g.conditions...) {
@@ -398,7 +436,11 @@ func (f Spec) generateEntities(service types.Service, file File) (err error) {
 }
 
 func (f Spec) generateEnums(service types.Service, file File) (err error) {
-	for _, g := range f.enumGenerators {
+	for _, name := range f.order {
+		g, ok := f.enumGenerators[name]
+		if !ok {
+			continue
+		}
 		enums := service.Enums
 		for _, enum := range enums {
 			if !checkEnumConditions(service, enum, g.conditions...) {
@@ -414,7 +456,11 @@ func (f Spec) generateEnums(service types.Service, file File) (err error) {
 }
 
 func (f Spec) generateArgumentsGroups(service types.Service, file File) (err error) {
-	for _, g := range f.argumentsGroupGenerators {
+	for _, name := range f.order {
+		g, ok := f.argumentsGroupGenerators[name]
+		if !ok {
+			continue
+		}
 		argsGroups := service.ArgumentsGroups
 		for _, argsGroup := range argsGroups {
 			if !checkArgumentsGroupConditions(service, argsGroup, g.conditions...) {
@@ -3,7 +3,10 @@ package files
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"io"
 	"path/filepath"
 	"regexp"
@@ -19,8 +22,9 @@ type goImportDef struct {
 
 type GoFile struct {
 	file
-	Pkg     string
-	imports [][]*goImportDef
+	Pkg             string
+	imports         [][]*goImportDef
+	optionalImports []*goImportDef
 }
 
 func NewGoFile(base string, path string, name string, overwrite bool, merge bool) *GoFile {
@@ -37,6 +41,7 @@ func NewGoFile(base string, path string, name string, overwrite bool, merge bool
 }
 
 func (f *GoFile) WriteTo(w io.Writer) (int64, error) {
+	f.addUsedOptionalImports()
 	lines := f.lines
 	f.lines = nil
 	// f.Cs(generateFileHeader(f.Overwrite())...)
@@ -57,6 +62,19 @@ func (f *GoFile) WriteTo(w io.Writer) (int64, error) {
 	return io.Copy(w, bytes.NewReader(b))
 }
 
+func (f *GoFile) MergeTo(w io.Writer, existing []byte) (int64, error) {
+	buf := new(bytes.Buffer)
+	_, err := f.WriteTo(buf)
+	if err != nil {
+		return 0, err
+	}
+	b, err := MergeGoSource(existing, buf.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, bytes.NewReader(b))
+}
+
 func (f *GoFile) writePackage() (lines []string) {
 	lines = append(lines, "package "+f.Pkg)
 	lines = append(lines, "")
@@ -145,6 +163,39 @@ func (f *GoFile) AddImport(alias string, path ...string) {
 	}
 }
 
+func (f *GoFile) AddOptionalImport(alias string, path string) {
+	f.optionalImports = append(f.optionalImports, &goImportDef{alias: alias, path: path})
+}
+
+func (f *GoFile) addUsedOptionalImports() {
+	if len(f.optionalImports) == 0 {
+		return
+	}
+	src := "package " + f.Pkg + "\n" + strs.Join(f.lines, "\n")
+	parsed, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return
+	}
+	used := map[string]bool{}
+	ast.Inspect(parsed, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	for _, i := range f.optionalImports {
+		name := i.alias
+		if name == "" {
+			name = filepath.Base(i.path)
+		}
+		if used[name] && !f.HasImport(i.path) {
+			f.AddImport(i.alias, i.path)
+		}
+	}
+}
+
 func (f *GoFile) I(path string) string {
 	f.AddImport("", path)
 	i := f.getImport(path)
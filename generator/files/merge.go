@@ -0,0 +1,282 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+	strs "strings"
+)
+
+func MergeGoSource(existing []byte, generated []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	ef, err := parser.ParseFile(fset, "existing.go", existing, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	gf, err := parser.ParseFile(fset, "generated.go", generated, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	declared := map[string]bool{}
+	for _, decl := range ef.Decls {
+		for _, key := range declKeys(decl) {
+			declared[key] = true
+		}
+	}
+	var added [][]mergedNode
+	used := map[string]bool{}
+	for _, decl := range gf.Decls {
+		var nodes []mergedNode
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if declared[funcKey(d)] {
+				continue
+			}
+			nodes = []mergedNode{{d.Doc, d}}
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range d.Specs {
+				isNew := false
+				for _, key := range specKeys(spec) {
+					if !declared[key] {
+						isNew = true
+					}
+				}
+				if isNew {
+					nodes = append(nodes, mergedNode{specDoc(spec), spec})
+				}
+			}
+			if len(nodes) == 0 {
+				continue
+			} else if len(nodes) == len(d.Specs) {
+				nodes = []mergedNode{{d.Doc, d}}
+			} else {
+				nodes = append([]mergedNode{{node: d}}, nodes...)
+			}
+		default:
+			continue
+		}
+		added = append(added, nodes)
+		for _, n := range nodes {
+			if _, ok := n.node.(*ast.GenDecl); ok && len(nodes) > 1 {
+				continue
+			}
+			for _, ident := range packageIdents(n.node) {
+				used[ident.Name] = true
+			}
+		}
+	}
+	if len(added) == 0 {
+		return existing, nil
+	}
+
+	imports, renames := mergeImports(ef, gf, used)
+	buf := new(bytes.Buffer)
+	buf.Write(insertImports(fset, existing, ef, imports))
+	for _, nodes := range added {
+		if len(nodes) == 1 {
+			fmt.Fprintf(buf, "\n%s\n", nodeSource(fset, generated, nodes[0].doc, nodes[0].node, renames))
+			continue
+		}
+		lines := []string{nodes[0].node.(*ast.GenDecl).Tok.String() + " ("}
+		for _, n := range nodes[1:] {
+			lines = append(lines, nodeSource(fset, generated, n.doc, n.node, renames))
+		}
+		lines = append(lines, ")")
+		fmt.Fprintf(buf, "\n%s\n", strs.Join(lines, "\n"))
+	}
+	return format.Source(buf.Bytes())
+}
+
+// mergedNode is a declaration, or a spec of one, added to the existing source.
+// Partially added declarations are kept as their declaration followed by the
+// added specs.
+type mergedNode struct {
+	doc  *ast.CommentGroup
+	node ast.Node
+}
+
+// mergeImports returns the imports of the generated source used by the added
+// declarations and missing from the existing source, keyed by both name and
+// path. Packages already imported are referred to by their existing name, and
+// added imports named like another package are given a new name, both
+// returned as renames of the generated names.
+func mergeImports(ef *ast.File, gf *ast.File, used map[string]bool) (imports []string, renames map[string]string) {
+	renames = map[string]string{}
+	names := map[string]string{}
+	paths := map[string]string{}
+	for _, imp := range ef.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := importName(imp)
+		names[name] = p
+		if name != "_" && name != "." {
+			paths[p] = name
+		}
+	}
+	for _, imp := range gf.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := importName(imp)
+		if !used[name] {
+			continue
+		}
+		if existing, ok := paths[p]; ok {
+			if existing != name {
+				renames[name] = existing
+			}
+			continue
+		}
+		alias := name
+		for i := 2; names[alias] != "" && names[alias] != p; i++ {
+			alias = name + strconv.Itoa(i)
+		}
+		names[alias], paths[p] = p, alias
+		if alias != name {
+			renames[name] = alias
+		}
+		if imp.Name != nil || alias != name {
+			imports = append(imports, alias+" "+imp.Path.Value)
+		} else {
+			imports = append(imports, imp.Path.Value)
+		}
+	}
+	return
+}
+
+// packageIdents returns the identifiers of the packages used in node.
+func packageIdents(node ast.Node) (idents []*ast.Ident) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				idents = append(idents, ident)
+			}
+		}
+		return true
+	})
+	return
+}
+
+func insertImports(fset *token.FileSet, src []byte, f *ast.File, imports []string) []byte {
+	if len(imports) == 0 {
+		return src
+	}
+	lines := strs.Join(imports, "\n")
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			continue
+		}
+		if d.Rparen.IsValid() {
+			at := fset.Position(d.Rparen).Offset
+			return splice(src, at, at, lines+"\n")
+		}
+		start, end := fset.Position(d.Specs[0].Pos()).Offset, fset.Position(d.Specs[0].End()).Offset
+		return splice(src, start, end, "(\n"+string(src[start:end])+"\n"+lines+"\n)")
+	}
+	at := fset.Position(f.Name.End()).Offset
+	return splice(src, at, at, "\n\nimport (\n"+lines+"\n)")
+}
+
+func splice(src []byte, start int, end int, s string) []byte {
+	b := make([]byte, 0, len(src)+len(s))
+	b = append(b, src[:start]...)
+	b = append(b, s...)
+	return append(b, src[end:]...)
+}
+
+func nodeSource(fset *token.FileSet, src []byte, doc *ast.CommentGroup, node ast.Node, renames map[string]string) string {
+	start := node.Pos()
+	if doc != nil {
+		start = doc.Pos()
+	}
+	offset := fset.Position(start).Offset
+	b := append([]byte(nil), src[offset:fset.Position(node.End()).Offset]...)
+	idents := packageIdents(node)
+	for i := len(idents) - 1; i >= 0; i-- {
+		if name, ok := renames[idents[i].Name]; ok {
+			at := fset.Position(idents[i].Pos()).Offset - offset
+			b = splice(b, at, at+len(idents[i].Name), name)
+		}
+	}
+	return string(b)
+}
+
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Doc
+	case *ast.ValueSpec:
+		return s.Doc
+	}
+	return nil
+}
+
+func declKeys(decl ast.Decl) (keys []string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		keys = append(keys, funcKey(d))
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			keys = append(keys, specKeys(spec)...)
+		}
+	}
+	return
+}
+
+func specKeys(spec ast.Spec) (keys []string) {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		keys = append(keys, s.Name.Name)
+	case *ast.ValueSpec:
+		for _, name := range s.Names {
+			if name.Name != "_" {
+				keys = append(keys, name.Name)
+			}
+		}
+	}
+	return
+}
+
+func funcKey(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return d.Name.Name
+	}
+	return receiverName(d.Recv.List[0].Type) + "." + d.Name.Name
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.ParenExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+func importName(imp *ast.ImportSpec) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+	p, _ := strconv.Unquote(imp.Path.Value)
+	name := path.Base(p)
+	if len(name) > 1 && name[0] == 'v' && strs.Trim(name[1:], "0123456789") == "" && path.Dir(p) != "." {
+		name = path.Base(path.Dir(p))
+	}
+	name = strs.TrimPrefix(name, "go-")
+	name = strs.TrimSuffix(name, ".go")
+	if i := strs.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	return strs.Replace(name, "-", "_", -1)
+}
@@ -0,0 +1,277 @@
+package files
+
+import (
+	"go/format"
+	"testing"
+)
+
+func TestMergeGoSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string
+		generated string
+		want      string
+	}{
+		{
+			name: "existing declarations are kept",
+			existing: `package p
+
+func A() int {
+	return 42
+}
+`,
+			generated: `package p
+
+func A() int {
+	return 0
+}
+
+func B() int {
+	return 0
+}
+`,
+			want: `package p
+
+func A() int {
+	return 42
+}
+
+func B() int {
+	return 0
+}
+`,
+		},
+		{
+			name: "methods are keyed by receiver",
+			existing: `package p
+
+type S struct{}
+
+type T struct{}
+
+func (s *S) M() {}
+`,
+			generated: `package p
+
+type S struct{}
+
+type T struct{}
+
+func (s *S) M() {}
+
+func (t T) M() {}
+`,
+			want: `package p
+
+type S struct{}
+
+type T struct{}
+
+func (s *S) M() {}
+
+func (t T) M() {}
+`,
+		},
+		{
+			name: "only new specs of a group are added",
+			existing: `package p
+
+const (
+	A = 1
+)
+`,
+			generated: `package p
+
+const (
+	A = 2
+	B = 3
+)
+`,
+			want: `package p
+
+const (
+	A = 1
+)
+
+const (
+	B = 3
+)
+`,
+		},
+		{
+			name: "imports are added only when used",
+			existing: `package p
+
+import "fmt"
+
+func A() {
+	fmt.Println()
+}
+`,
+			generated: `package p
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func A() {
+	fmt.Println(strings.ToUpper(""))
+}
+
+func B() {
+	os.Exit(0)
+}
+`,
+			want: `package p
+
+import (
+	"fmt"
+	"os"
+)
+
+func A() {
+	fmt.Println()
+}
+
+func B() {
+	os.Exit(0)
+}
+`,
+		},
+		{
+			name: "named imports are kept named",
+			existing: `package p
+`,
+			generated: `package p
+
+import kit_log "github.com/go-kit/kit/log"
+
+func A(l kit_log.Logger) {}
+`,
+			want: `package p
+
+import (
+	kit_log "github.com/go-kit/kit/log"
+)
+
+func A(l kit_log.Logger) {}
+`,
+		},
+		{
+			name: "packages already imported keep their existing name",
+			existing: `package p
+
+import "github.com/go-kit/kit/log"
+
+func A(l log.Logger) {}
+`,
+			generated: `package p
+
+import kit_log "github.com/go-kit/kit/log"
+
+func A(l kit_log.Logger) {}
+
+func B(l kit_log.Logger) kit_log.Logger {
+	return kit_log.With(l)
+}
+`,
+			want: `package p
+
+import "github.com/go-kit/kit/log"
+
+func A(l log.Logger) {}
+
+func B(l log.Logger) log.Logger {
+	return log.With(l)
+}
+`,
+		},
+		{
+			name: "added imports named like another package are renamed",
+			existing: `package p
+
+import "github.com/other/log"
+
+func A() {
+	log.Print()
+}
+`,
+			generated: `package p
+
+import (
+	"github.com/go-kit/kit/log"
+	"strings"
+)
+
+func B(l log.Logger) string {
+	return strings.TrimSpace("")
+}
+`,
+			want: `package p
+
+import (
+	"github.com/other/log"
+	log2 "github.com/go-kit/kit/log"
+	"strings"
+)
+
+func A() {
+	log.Print()
+}
+
+func B(l log2.Logger) string {
+	return strings.TrimSpace("")
+}
+`,
+		},
+		{
+			name: "nothing is changed when everything is declared",
+			existing: `package p
+
+// A is edited.
+func A() {}
+`,
+			generated: `package p
+
+func A() {
+	panic("")
+}
+`,
+			want: `package p
+
+// A is edited.
+func A() {}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := MergeGoSource([]byte(test.existing), []byte(test.generated))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := format.Source([]byte(test.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+			}
+			again, err := MergeGoSource(got, []byte(test.generated))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(got) {
+				t.Fatalf("second merge changed the source:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestMergeGoSourceInvalid(t *testing.T) {
+	if _, err := MergeGoSource([]byte("package p\nfunc {"), []byte("package p\n")); err == nil {
+		t.Fatal("expected an error for invalid existing source")
+	}
+}
@@ -0,0 +1,100 @@
+package files
+
+import (
+	"fmt"
+	"io"
+	strs "strings"
+)
+
+type RawFile struct {
+	file
+	commentFormat string
+}
+
+func NewRawFile(base string, path string, name string, ext string, overwrite bool, merge bool) *RawFile {
+	f := &RawFile{}
+	f.base = base
+	f.path = path
+	f.name = name
+	f.extension = ext
+	f.overwrite = overwrite
+	f.merge = merge
+	switch strs.ToLower(ext) {
+	case "go", "proto":
+		f.commentFormat = "// %s"
+	case "yaml", "yml", "sh", "toml":
+		f.commentFormat = "# %s"
+	}
+	return f
+}
+
+func (f *RawFile) SetContent(content string) {
+	f.lines = strs.Split(strs.TrimSuffix(content, "\n"), "\n")
+}
+
+func (f *RawFile) WriteTo(w io.Writer) (int64, error) {
+	return f.writeLines(w)
+}
+
+func (f *RawFile) MergeTo(w io.Writer, existing []byte) (int64, error) {
+	if f.extension != "go" {
+		n, err := w.Write(existing)
+		return int64(n), err
+	}
+	buf := new(strs.Builder)
+	_, err := f.writeLines(buf)
+	if err != nil {
+		return 0, err
+	}
+	b, err := MergeGoSource(existing, []byte(buf.String()))
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+func (f *RawFile) FormatComments(cs ...string) (fcs []string) {
+	if f.commentFormat == "" {
+		return nil
+	}
+	for _, c := range cs {
+		if c == "" {
+			fcs = append(fcs, "")
+			continue
+		}
+		fcs = append(fcs, fmt.Sprintf(f.commentFormat, c))
+	}
+	return
+}
+
+func (f *RawFile) C(s string) {
+	f.Cs(s)
+}
+
+func (f *RawFile) Cs(s ...string) {
+	f.Ps(f.FormatComments(s...)...)
+}
+
+func (f *RawFile) Cf(format string, args ...interface{}) {
+	f.C(fmt.Sprintf(format, args...))
+}
+
+func (f *RawFile) P(s string) {
+	f.lines = append(f.lines, s)
+}
+
+func (f *RawFile) Pf(format string, args ...interface{}) {
+	f.P(fmt.Sprintf(format, args...))
+}
+
+func (f *RawFile) Ps(s ...string) {
+	f.lines = append(f.lines, s...)
+}
+
+func (f *RawFile) HasImport(path ...string) bool {
+	return false
+}
+
+func (f *RawFile) AddImport(alias string, path ...string) {
+}
@@ -1,13 +1,20 @@
 package generator
 
-import "github.com/wlMalk/goms/generator/file"
+import (
+	"strings"
+
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/plugin"
+)
 
 var builtInGenerators []GeneratorOption = []GeneratorOption{
 	DockerfileFileSpec,
 	ProtoBufServiceDefinitionsFileSpec,
+	OpenAPIFileSpec,
 	ServiceMainFileSpec,
 	ServiceStartCMDFileSpec,
 	CachingMiddlewareFileSpec,
+	PublishingMiddlewareFileSpec,
 	ConvertersFileSpec,
 	HandlersFileSpec,
 	ServiceImplementationFileSpec,
@@ -31,6 +38,19 @@ var builtInGenerators []GeneratorOption = []GeneratorOption{
 	HTTPRequestsFileSpec,
 	HTTPResponsesFileSpec,
 	HTTPServerFileSpec,
+	JSONRPCClientFileSpec,
+	GlobalJSONRPCClientFileSpec,
+	JSONRPCDecodersFileSpec,
+	JSONRPCEncodersFileSpec,
+	JSONRPCMessagesFileSpec,
+	JSONRPCServerFileSpec,
+	MQClientFileSpec,
+	GlobalMQClientFileSpec,
+	MQDecodersFileSpec,
+	MQEncodersFileSpec,
+	MQMessagesFileSpec,
+	MQServerFileSpec,
+	ProtoEntitiesConvertersFileSpec,
 	ProtoRequestsConvertersFileSpec,
 	ProtoResponsesConvertersFileSpec,
 	RequestsFileSpec,
@@ -48,6 +68,9 @@ var builtInFileCreators []GeneratorOption = []GeneratorOption{
 	GeneratorOption(func(generator *Generator) {
 		generator.AddCreator("Dockerfile", TextFileCreator(""))
 	}),
+	GeneratorOption(func(generator *Generator) {
+		generator.AddCreator("yaml", TextFileCreator("yaml"))
+	}),
 }
 
 func Default(opts ...GeneratorOption) *Generator {
@@ -64,3 +87,39 @@ func New(opts ...GeneratorOption) *Generator {
 	}
 	return g
 }
+
+func IncludeSpecs(names ...string) GeneratorOption {
+	return func(generator *Generator) {
+		included := map[string]bool{}
+		for _, name := range names {
+			included[strings.ToLower(name)] = true
+		}
+		for _, name := range generator.Specs() {
+			if !included[name] {
+				generator.RemoveSpec(name)
+			}
+		}
+	}
+}
+
+func ExcludeSpecs(names ...string) GeneratorOption {
+	return func(generator *Generator) {
+		for _, name := range names {
+			generator.RemoveSpec(name)
+		}
+	}
+}
+
+func SpecPolicy(name string, overwrite bool, merge bool) GeneratorOption {
+	return func(generator *Generator) {
+		if generator.HasSpec(name) {
+			generator.AddSpec(name, generator.GetSpec(name).Overwrite(overwrite, nil).Merge(merge, nil))
+		}
+	}
+}
+
+func Plugin(name string, parameter string) GeneratorOption {
+	return func(generator *Generator) {
+		generator.AddPlugin(plugin.New(name, parameter))
+	}
+}
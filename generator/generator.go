@@ -1,17 +1,27 @@
 package generator
 
 import (
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/files"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/plugin"
 	"github.com/wlMalk/goms/parser/types"
 )
 
+// PluginsSpec is the spec name recorded for the files generated by plugins.
+const PluginsSpec = "plugins"
+
 type GeneratorOption func(generator *Generator)
 
 type Generator struct {
 	creators map[string]file.Creator
 	specs    map[string]file.Spec
+	plugins  []*plugin.Plugin
 }
 
 func (g *Generator) AddCreator(fileType string, creator file.Creator) {
@@ -78,19 +88,79 @@ func (g *Generator) AddEnumGeneratorWithConditions(name string, generatorName st
 	g.AddSpec(name, g.GetSpec(name).AddEnumGenerator(generatorName, generator, conds...))
 }
 
+func (g *Generator) AddPlugin(p *plugin.Plugin) {
+	g.plugins = append(g.plugins, p)
+}
+
 func (g *Generator) GetSpec(name string) file.Spec {
 	return g.specs[strings.ToLower(name)]
 }
 
-func (g *Generator) Generate(service types.Service) (files Files, err error) {
-	for _, s := range g.specs {
+func (g *Generator) HasSpec(name string) bool {
+	_, ok := g.specs[strings.ToLower(name)]
+	return ok
+}
+
+func (g *Generator) Specs() (names []string) {
+	for name := range g.specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// GeneratedSpecs returns the names of the specs a call to Generate runs,
+// including the one of the plugins.
+func (g *Generator) GeneratedSpecs() []string {
+	return append(g.Specs(), PluginsSpec)
+}
+
+func (g *Generator) Generate(service types.Service) (fs Files, err error) {
+	for name, s := range g.specs {
 		file, err := s.Generate(service, g.creators[strings.ToLower(s.Type())])
 		if err != nil {
 			return nil, err
 		}
 		if file != nil {
-			files = append(files, file)
+			if goFile, ok := file.(*files.GoFile); ok {
+				addOptionalImports(goFile, service)
+			}
+			fs = append(fs, SpecFile{Spec: name, File: file})
+		}
+	}
+	for _, p := range g.plugins {
+		pluginFiles, err := p.Run(service)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range pluginFiles {
+			fs = append(fs, SpecFile{Spec: PluginsSpec, File: pluginFile(service, f)})
 		}
 	}
 	return
 }
+
+func pluginFile(service types.Service, f plugin.File) file.File {
+	p := filepath.FromSlash(path.Clean(f.Path))
+	dir, name := filepath.Split(p)
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+	if ext != "" {
+		name = strings.TrimSuffix(name, "."+ext)
+	}
+	raw := files.NewRawFile(service.Path, filepath.Clean(dir), name, ext, f.Overwrite, f.Merge)
+	raw.SetContent(f.Content)
+	return raw
+}
+
+func addOptionalImports(file *files.GoFile, service types.Service) {
+	if helpers.HasTypesDefinitions(service) {
+		file.AddOptionalImport("", service.ImportPath+"/pkg/service/types")
+	}
+	for _, t := range helpers.GetImportedTypes(service) {
+		if t.Pkg == path.Base(t.PkgImportPath) {
+			file.AddOptionalImport("", t.PkgImportPath)
+		} else {
+			file.AddOptionalImport(t.Pkg, t.PkgImportPath)
+		}
+	}
+}
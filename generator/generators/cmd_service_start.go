@@ -72,6 +72,11 @@ func ServiceStartCMDFunc(file file.File, service types.Service) error {
 		file.AddImport("kit_grpc", "github.com/go-kit/kit/transport/grpc")
 		file.AddImport("goms_grpc", "github.com/wlMalk/goms/goms/transport/grpc")
 	}
+	if helpers.IsJSONRPCServerEnabled(service) {
+		file.AddImport(strings.ToSnakeCase(service.Name)+"_jsonrpc_server", service.ImportPath, "/pkg/transport/jsonrpc/server")
+		file.AddImport("goms_http", "github.com/wlMalk/goms/goms/transport/http")
+		file.AddImport("goms_jsonrpc", "github.com/wlMalk/goms/goms/transport/jsonrpc")
+	}
 
 	file.Pf("func Start(")
 	if service.Generate.Has(constants.ServiceGenerateLoggerFlag) || helpers.IsLoggingEnabled(service) {
@@ -154,6 +159,23 @@ func ServiceStartCMDFunc(file file.File, service types.Service) error {
 		file.Pf(")")
 		file.Pf("})")
 	}
+	if helpers.IsJSONRPCServerEnabled(service) {
+		file.Pf("")
+		file.Pf("jsonrpcAddr := \":8082\" // TODO: use normal address")
+		file.Pf("g.Go(func() error {")
+		file.Pf("return serveJSONRPC(")
+		file.Pf("ctx,")
+		file.Pf("&endpoints,")
+		file.Pf("jsonrpcAddr,")
+		if service.Generate.Has(constants.ServiceGenerateLoggerFlag) {
+			file.Pf("log.With(logger, \"transport\", \"JSONRPC\"),")
+		}
+		if helpers.IsTracingEnabled(service) && service.Generate.Has(constants.ServiceGenerateLoggerFlag) {
+			file.Pf("tracer,")
+		}
+		file.Pf(")")
+		file.Pf("})")
+	}
 	if helpers.IsServerEnabled(service) {
 		file.Pf("")
 		if service.Generate.Has(constants.ServiceGenerateLoggerFlag) {
@@ -381,3 +403,60 @@ func ServiceMainServeHTTPFunc(file file.File, service types.Service) error {
 	file.Pf("")
 	return nil
 }
+
+func ServiceMainServeJSONRPCFunc(file file.File, service types.Service) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	serviceNameSnake := strings.ToSnakeCase(service.Name)
+	file.Pf("func serveJSONRPC(")
+	file.Pf("ctx context.Context,")
+	file.Pf("endpoints *transport.%s,", serviceName)
+	file.Pf("addr string,")
+	if service.Generate.Has(constants.ServiceGenerateLoggerFlag) || helpers.IsLoggingEnabled(service) {
+		file.Pf("logger log.Logger,")
+	}
+	if helpers.IsTracingEnabled(service) && service.Generate.Has(constants.ServiceGenerateLoggerFlag) {
+		file.Pf("tracer opentracinggo.Tracer,")
+	}
+	file.Pf(") error {")
+	file.Pf("server := goms_jsonrpc.NewServer()")
+	file.Pf("server.Addr = addr")
+	file.Pf("")
+	file.Pf("%s_jsonrpc_server.RegisterSpecial(server, endpoints,", serviceNameSnake)
+	file.Pf("func(method string) (opts []goms_jsonrpc.ServerOption) {")
+	file.Pf("opts = append(")
+	file.Pf("opts, goms_jsonrpc.ServerBefore(")
+	if helpers.IsTracingEnabled(service) && service.Generate.Has(constants.ServiceGenerateLoggerFlag) {
+		file.Pf("opentracing.HTTPToContext(tracer, method, logger),")
+	}
+	file.Pf("goms_http.MethodInjector(\"%s\", method),", helpers.GetName(serviceName, service.Alias))
+	file.Pf("goms_http.RequestIDCreator(),")
+	file.Pf("goms_http.CorrelationIDExtractor(),")
+	if helpers.IsLoggingEnabled(service) {
+		file.Pf("goms_http.LoggerInjector(logger),")
+	}
+	file.Pf("),")
+	file.Pf(")")
+	file.Pf("return")
+	file.Pf("},")
+	file.Pf(")")
+	file.Pf("")
+	if service.Generate.Has(constants.ServiceGenerateLoggerFlag) {
+		file.Pf("logger.Log(\"listening on\", addr)")
+	}
+	file.Pf("ch := make(chan error)")
+	file.Pf("go func() {")
+	file.Pf("ch <- server.ListenAndServe()")
+	file.Pf("}()")
+	file.Pf("select {")
+	file.Pf("case err := <-ch:")
+	file.Pf("if err == http.ErrServerClosed {")
+	file.Pf("return nil")
+	file.Pf("}")
+	file.Pf("return fmt.Errorf(\"jsonrpc server: serve: %%v\", err)")
+	file.Pf("case <-ctx.Done():")
+	file.Pf("return server.Shutdown(context.Background())")
+	file.Pf("}")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
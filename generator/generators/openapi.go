@@ -0,0 +1,342 @@
+package generators
+
+import (
+	"strconv"
+	strs "strings"
+
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func OpenAPIDocument(file file.File, service types.Service) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	file.P("openapi: 3.0.3")
+	file.P("info:")
+	file.Pf("  title: %s", strconv.Quote(helpers.GetName(serviceName, service.Alias)))
+	file.Pf("  version: %s", strconv.Quote(service.Version.FullString()))
+	if len(service.Docs) > 0 {
+		file.Pf("  description: %s", strconv.Quote(strs.Join(service.Docs, " ")))
+	}
+	file.P("paths:")
+	var paths []string
+	methods := map[string][]types.Method{}
+	for _, method := range helpers.GetMethodsWithHTTPServerEnabled(service) {
+		path := openAPIPath(getMethodURI(service, method))
+		if _, ok := methods[path]; !ok {
+			paths = append(paths, path)
+		}
+		methods[path] = append(methods[path], method)
+	}
+	for _, path := range paths {
+		file.Pf("  %s:", path)
+		for _, method := range methods[path] {
+			OpenAPIOperation(file, service, method)
+		}
+	}
+	file.P("components:")
+	file.P("  schemas:")
+	file.P("    Problem:")
+	file.P("      type: object")
+	file.P("      properties:")
+	for _, name := range []string{"type", "title", "kind", "detail"} {
+		file.Pf("        %s:", name)
+		file.P("          type: string")
+	}
+	file.P("        status:")
+	file.P("          type: integer")
+	file.P("        violations:")
+	file.P("          type: array")
+	file.P("          items:")
+	file.P("            $ref: \"#/components/schemas/FieldViolation\"")
+	file.P("    FieldViolation:")
+	file.P("      type: object")
+	file.P("      properties:")
+	for _, name := range []string{"field", "rule", "message"} {
+		file.Pf("        %s:", name)
+		file.P("          type: string")
+	}
+	return nil
+}
+
+func OpenAPIOperation(file file.File, service types.Service, method types.Method) {
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("    %s:", strs.ToLower(getMethodHTTPMethod(method)))
+	file.Pf("      operationId: %s", helpers.GetName(methodName, method.Alias))
+	file.P("      tags:")
+	file.Pf("        - %s", helpers.GetName(strings.ToUpperFirst(service.Name), service.Alias))
+	if len(method.Docs) > 0 {
+		file.Pf("      description: %s", strconv.Quote(strs.Join(method.Docs, " ")))
+	}
+	var params []*types.Argument
+	for _, arg := range method.Arguments {
+		if arg.Options.HTTP.Origin != "BODY" {
+			params = append(params, arg)
+		}
+	}
+	if len(params) > 0 {
+		file.P("      parameters:")
+		for _, arg := range params {
+			file.Pf("        - name: %s", openAPIParameterName(arg))
+			file.Pf("          in: %s", strs.ToLower(arg.Options.HTTP.Origin))
+			file.Pf("          required: %t", arg.Options.HTTP.Origin == "PATH" || !arg.IsOptional)
+			if len(arg.Docs) > 0 {
+				file.Pf("          description: %s", strconv.Quote(strs.Join(arg.Docs, " ")))
+			}
+			OpenAPISchema(file, "          ", "schema", arg.Type)
+		}
+	}
+	if hasHTTPRequestBody(method) && method.Options.HTTP.Stream != "ws" {
+		file.P("      requestBody:")
+		file.P("        required: true")
+		file.P("        content:")
+		file.P("          application/json:")
+		file.P("            schema:")
+		file.Pf("              $ref: \"#/components/schemas/%sRequestBody\"", methodName)
+	}
+	file.P("      responses:")
+	switch method.Options.HTTP.Stream {
+	case "sse":
+		file.P("        \"200\":")
+		file.P("          description: OK")
+		file.P("          content:")
+		file.P("            text/event-stream:")
+		file.P("              schema:")
+		file.Pf("                $ref: \"#/components/schemas/%sEvent\"", methodName)
+	case "ws":
+		file.P("        \"101\":")
+		file.P("          description: Switching Protocols")
+	default:
+		file.P("        \"200\":")
+		file.P("          description: OK")
+		if len(method.Results) > 0 {
+			file.P("          content:")
+			file.P("            application/json:")
+			file.P("              schema:")
+			file.Pf("                $ref: \"#/components/schemas/%sResponse\"", methodName)
+		}
+	}
+	file.P("        default:")
+	file.P("          description: Error")
+	file.P("          content:")
+	file.P("            application/problem+json:")
+	file.P("              schema:")
+	file.P("                $ref: \"#/components/schemas/Problem\"")
+}
+
+func OpenAPIMethodSchemas(file file.File, service types.Service, method types.Method) error {
+	methodName := strings.ToUpperFirst(method.Name)
+	if hasHTTPRequestBody(method) {
+		var properties []openAPIProperty
+		for _, arg := range getArgumentsOfOrigin(method.Arguments, "BODY") {
+			properties = append(properties, openAPIProperty{
+				name:     helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias),
+				docs:     arg.Docs,
+				t:        arg.Type,
+				required: !arg.IsOptional,
+			})
+		}
+		openAPIObject(file, methodName+"RequestBody", nil, properties)
+	}
+	if method.Options.HTTP.Stream == "sse" {
+		OpenAPISchema(file, "    ", methodName+"Event", method.Results[0].Type.Elem())
+	} else if len(method.Results) > 0 && method.Options.HTTP.Stream == "" {
+		var properties []openAPIProperty
+		for _, field := range method.Results {
+			properties = append(properties, openAPIProperty{
+				name: helpers.GetName(strings.ToLowerFirst(field.Name), field.Alias),
+				docs: field.Docs,
+				t:    field.Type,
+			})
+		}
+		openAPIObject(file, methodName+"Response", nil, properties)
+	}
+	return nil
+}
+
+func OpenAPIEntitySchema(file file.File, service types.Service, entity types.Entity) error {
+	var properties []openAPIProperty
+	for _, field := range entity.Fields {
+		name := strings.ToUpperFirst(field.Name)
+		if tag, ok := field.Tags["json"]; entity.IsImport && ok && len(tag) > 0 && tag[0] != "" {
+			if tag[0] == "-" {
+				continue
+			}
+			name = tag[0]
+		}
+		properties = append(properties, openAPIProperty{name: name, docs: field.Docs, t: field.Type})
+	}
+	openAPIObject(file, strings.ToUpperFirst(entity.Name), entity.Docs, properties)
+	return nil
+}
+
+func OpenAPIArgumentsGroupSchema(file file.File, service types.Service, argGroup types.ArgumentsGroup) error {
+	var properties []openAPIProperty
+	for _, arg := range argGroup.Arguments {
+		properties = append(properties, openAPIProperty{name: strings.ToUpperFirst(arg.Name), docs: arg.Docs, t: arg.Type})
+	}
+	openAPIObject(file, strings.ToUpperFirst(argGroup.Name), argGroup.Docs, properties)
+	return nil
+}
+
+func OpenAPIEnumSchema(file file.File, service types.Service, enum types.Enum) error {
+	file.Pf("    %s:", strings.ToUpperFirst(enum.Name))
+	file.P("      type: integer")
+	if len(enum.Docs) > 0 {
+		file.Pf("      description: %s", strconv.Quote(strs.Join(enum.Docs, " ")))
+	}
+	file.P("      enum:")
+	for _, c := range enum.Cases {
+		file.Pf("        - %d", c.Value)
+	}
+	file.P("      x-enum-varnames:")
+	for _, c := range enum.Cases {
+		file.Pf("        - %s", strs.ToUpper(strings.ToSnakeCase(c.Name)))
+	}
+	return nil
+}
+
+func OpenAPISchema(file file.File, indent string, key string, t *types.Type) {
+	lines := openAPISchemaLines(t)
+	if len(lines) == 0 {
+		file.Pf("%s%s: {}", indent, key)
+		return
+	}
+	file.Pf("%s%s:", indent, key)
+	for _, line := range lines {
+		file.Pf("%s  %s", indent, line)
+	}
+}
+
+type openAPIProperty struct {
+	name     string
+	docs     []string
+	t        *types.Type
+	required bool
+}
+
+func openAPIObject(file file.File, name string, docs []string, properties []openAPIProperty) {
+	file.Pf("    %s:", name)
+	file.P("      type: object")
+	if len(docs) > 0 {
+		file.Pf("      description: %s", strconv.Quote(strs.Join(docs, " ")))
+	}
+	var required []string
+	for _, p := range properties {
+		if p.required {
+			required = append(required, p.name)
+		}
+	}
+	if len(required) > 0 {
+		file.P("      required:")
+		for _, r := range required {
+			file.Pf("        - %s", r)
+		}
+	}
+	if len(properties) == 0 {
+		return
+	}
+	file.P("      properties:")
+	for _, p := range properties {
+		if len(p.docs) == 0 || openAPIIsRef(p.t) {
+			OpenAPISchema(file, "        ", p.name, p.t)
+			continue
+		}
+		file.Pf("        %s:", p.name)
+		file.Pf("          description: %s", strconv.Quote(strs.Join(p.docs, " ")))
+		for _, line := range openAPISchemaLines(p.t) {
+			file.Pf("          %s", line)
+		}
+	}
+}
+
+func openAPISchemaLines(t *types.Type) (lines []string) {
+	switch {
+	case t.IsMap:
+		lines = append(lines, "type: object")
+		value := openAPISchemaLines(t.Value)
+		if len(value) == 0 {
+			return append(lines, "additionalProperties: {}")
+		}
+		lines = append(lines, "additionalProperties:")
+		for _, line := range value {
+			lines = append(lines, "  "+line)
+		}
+		return
+	case t.IsBytes || ((t.IsSlice || t.IsVariadic) && t.IsBuiltin && t.Name == "byte"):
+		return []string{"type: string", "format: byte"}
+	case t.IsSlice || t.IsVariadic:
+		elem := *t
+		elem.IsSlice = false
+		elem.IsVariadic = false
+		lines = append(lines, "type: array")
+		items := openAPISchemaLines(&elem)
+		if len(items) == 0 {
+			return append(lines, "items: {}")
+		}
+		lines = append(lines, "items:")
+		for _, line := range items {
+			lines = append(lines, "  "+line)
+		}
+		return
+	case openAPIIsRef(t):
+		return []string{"$ref: \"#/components/schemas/" + strings.ToUpperFirst(t.Name) + "\""}
+	case t.IsBuiltin:
+		typ, format := openAPIBuiltin(t.Name)
+		lines = append(lines, "type: "+typ)
+		if format != "" {
+			lines = append(lines, "format: "+format)
+		}
+		return
+	case t.IsImport && t.PkgImportPath == "time" && t.Name == "Time":
+		return []string{"type: string", "format: date-time"}
+	}
+	return nil
+}
+
+func openAPIIsRef(t *types.Type) bool {
+	return !t.IsMap && !t.IsSlice && !t.IsVariadic && (t.IsEntity || t.IsEnum || t.IsArgumentsGroup)
+}
+
+func openAPIBuiltin(name string) (typ string, format string) {
+	switch name {
+	case "bool":
+		return "boolean", ""
+	case "string":
+		return "string", ""
+	case "int", "int64", "uint", "uint64":
+		return "integer", "int64"
+	case "float32":
+		return "number", "float"
+	case "float64":
+		return "number", "double"
+	default:
+		return "integer", "int32"
+	}
+}
+
+func openAPIParameterName(arg *types.Argument) string {
+	switch arg.Options.HTTP.Origin {
+	case "HEADER":
+		return strings.ToKebabCase(helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias))
+	case "PATH":
+		return strings.ToSnakeCase(arg.Name)
+	default:
+		return strings.ToSnakeCase(helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias))
+	}
+}
+
+func openAPIPath(uri string) string {
+	segments := strs.Split(uri, "/")
+	for i, segment := range segments {
+		if strs.HasPrefix(segment, ":") || strs.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strs.Join(segments, "/")
+}
+
+func hasHTTPRequestBody(method types.Method) bool {
+	return (method.Options.HTTP.Method == "POST" || method.Options.HTTP.Method == "PUT") && hasArgumentsOfOrigin(method.Arguments, "BODY")
+}
@@ -84,15 +84,20 @@ func ProtoBufServiceDefinition(file file.File, service types.Service) error {
 		if method.Generate.HasNone(constants.MethodGenerateGRPCServerFlag, constants.MethodGenerateGRPCClientFlag) {
 			continue
 		}
-		if len(method.Arguments) > 0 && len(method.Arguments) > 0 {
-			file.Pf("\trpc %s (%sRequest) returns (%sResponse);", methodName, methodName, methodName)
-		} else if len(method.Arguments) > 0 {
-			file.Pf("\trpc %s (%sRequest) returns (empty.Empty);", methodName, methodName)
-		} else if len(method.Results) > 0 {
-			file.Pf("\trpc %s (empty.Empty) returns (%sResponse);", methodName, methodName)
-		} else {
-			file.Pf("\trpc %s (empty.Empty) returns (empty.Empty);", methodName)
+		req, res := "empty.Empty", "empty.Empty"
+		if len(method.Arguments) > 0 {
+			req = methodName + "Request"
 		}
+		if len(method.Results) > 0 {
+			res = methodName + "Response"
+		}
+		if method.IsClientStreaming() {
+			req = "stream " + req
+		}
+		if method.IsServerStreaming() {
+			res = "stream " + res
+		}
+		file.Pf("\trpc %s (%s) returns (%s);", methodName, req, res)
 	}
 	file.Pf("}")
 	file.Pf("")
@@ -0,0 +1,118 @@
+package generators
+
+import (
+	strs "strings"
+
+	"github.com/wlMalk/goms/constants"
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func PublishingMiddlewareStruct(file file.File, service types.Service) error {
+	file.AddImport("", service.ImportPath, "/pkg/service/handlers")
+	file.AddImport("", "github.com/wlMalk/goms/goms/events")
+	file.P("type publishingMiddleware struct {")
+	file.P("publisher events.Publisher")
+	file.P("next      handlers.RequestResponseHandler")
+	file.P("}")
+	file.P("")
+	return nil
+}
+
+func PublishingMiddlewareNewFunc(file file.File, service types.Service) error {
+	file.AddImport("", service.ImportPath, "/pkg/service/handlers")
+	file.AddImport("", "github.com/wlMalk/goms/goms/events")
+	file.P("func PublishingMiddleware(publisher events.Publisher) RequestResponseMiddleware {")
+	file.P("return func(next handlers.RequestResponseHandler) handlers.RequestResponseHandler {")
+	file.P("return &publishingMiddleware{")
+	file.P("publisher: publisher,")
+	file.P("next:      next,")
+	file.P("}")
+	file.P("}")
+	file.P("}")
+	file.P("")
+	return nil
+}
+
+func PublishingMiddlewareMethodFunc(file file.File, service types.Service, method types.Method) error {
+	file.AddImport("", "context")
+	serviceName := strings.ToUpperFirst(service.Name)
+	methodName := strings.ToUpperFirst(method.Name)
+	args := []string{"ctx context.Context"}
+	argsInCall := []string{"ctx"}
+	if len(method.Arguments) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/requests")
+		args = append(args, "req *requests."+methodName+"Request")
+		argsInCall = append(argsInCall, "req")
+	}
+	results := []string{"err error"}
+	resultsInCall := []string{"err"}
+	if len(method.Results) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/responses")
+		results = append([]string{"res *responses." + methodName + "Response"}, results...)
+		resultsInCall = append([]string{"res"}, resultsInCall...)
+	}
+	file.Pf("func (m *publishingMiddleware) %s(%s) (%s) {", methodName, strs.Join(args, ", "), strs.Join(results, ", "))
+	if !method.Generate.Has(constants.MethodGenerateMiddlewareFlag) || len(method.Options.Events.Publishes) == 0 {
+		file.Pf("return m.next.%s(%s)", methodName, strs.Join(argsInCall, ", "))
+		file.Pf("}")
+		file.Pf("")
+		return nil
+	}
+	file.AddImport("", "github.com/wlMalk/goms/goms/events")
+	returnErr := "return err"
+	if len(method.Results) > 0 {
+		returnErr = "return nil, err"
+	}
+	source := helpers.GetName(serviceName, service.Alias) + "." + helpers.GetName(methodName, method.Alias)
+	file.Pf("%s = m.next.%s(%s)", strs.Join(resultsInCall, ", "), methodName, strs.Join(argsInCall, ", "))
+	file.Pf("if err != nil {")
+	file.Pf(returnErr)
+	file.Pf("}")
+	file.Pf("var published []*events.Event")
+	file.Pf("var event *events.Event")
+	for _, entity := range method.Options.Events.Publishes {
+		data, isPointer := publishedData(method, entity)
+		if isPointer {
+			file.Pf("if %s != nil {", data)
+		}
+		file.Pf("if event, err = events.New(ctx, \"%s\", \"%s\", %s); err != nil {", source, entity, data)
+		file.Pf(returnErr)
+		file.Pf("}")
+		file.Pf("published = append(published, event)")
+		if isPointer {
+			file.Pf("}")
+		}
+	}
+	file.Pf("if len(published) > 0 {")
+	file.Pf("if err = m.publisher.Publish(ctx, published...); err != nil {")
+	file.Pf(returnErr)
+	file.Pf("}")
+	file.Pf("}")
+	if len(method.Results) > 0 {
+		file.Pf("return res, nil")
+	} else {
+		file.Pf("return nil")
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+// publishedData returns the result, or else the argument, published as the
+// given entity.
+func publishedData(method types.Method, entity string) (data string, isPointer bool) {
+	for _, result := range method.Results {
+		if result.Type.IsPublishable(entity) {
+			return "res." + strings.ToUpperFirst(result.Name), result.Type.IsPointer
+		}
+	}
+	for _, arg := range method.Arguments {
+		if arg.Type.IsPublishable(entity) {
+			return "req." + strings.ToUpperFirst(arg.Name), arg.Type.IsPointer
+		}
+	}
+	return "nil", false
+}
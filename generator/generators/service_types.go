@@ -13,7 +13,7 @@ func ServiceEntityType(file file.File, service types.Service, entity types.Entit
 	file.Pf("type %s struct {", entityName)
 	for _, field := range entity.Fields {
 		fieldName := strings.ToUpperFirst(field.Name)
-		file.Pf("%s %s", fieldName, field.Type.GoType())
+		file.Pf("%s %s", fieldName, localType(field.Type).GoType())
 	}
 	file.Pf("}")
 	file.Pf("")
@@ -25,7 +25,7 @@ func ServiceArgumentsGroupType(file file.File, service types.Service, argGroup t
 	file.Pf("type %s struct {", argGroupName)
 	for _, arg := range argGroup.Arguments {
 		argName := strings.ToUpperFirst(arg.Name)
-		file.Pf("%s %s", argName, arg.Type.GoType())
+		file.Pf("%s %s", argName, localType(arg.Type).GoType())
 	}
 	file.Pf("}")
 	file.Pf("")
@@ -44,3 +44,16 @@ func ServiceEnumType(file file.File, service types.Service, enum types.Enum) err
 	file.Pf("")
 	return nil
 }
+
+func localType(t *types.Type) *types.Type {
+	c := *t
+	if !c.IsImport {
+		c.IsEntity = false
+		c.IsEnum = false
+		c.IsArgumentsGroup = false
+	}
+	if c.Value != nil {
+		c.Value = localType(c.Value)
+	}
+	return &c
+}
@@ -1,6 +1,8 @@
 package generators
 
 import (
+	"fmt"
+	"strconv"
 	strs "strings"
 
 	"github.com/wlMalk/goms/constants"
@@ -23,6 +25,30 @@ func ValidatingValidatorsTypes(file file.File, service types.Service) error {
 	return nil
 }
 
+func ValidatingRegexps(file file.File, service types.Service) error {
+	var regexps []string
+	for _, method := range helpers.GetMethodsWithValidatingEnabled(service) {
+		for _, arg := range method.Arguments {
+			for i, validator := range arg.Options.Validators {
+				if validator.Name == constants.ValidatorRegex {
+					regexps = append(regexps, fmt.Sprintf("%s = regexp.MustCompile(%s)", validatorRegexpName(method, arg, i), strconv.Quote(validator.Args[0])))
+				}
+			}
+		}
+	}
+	if len(regexps) == 0 {
+		return nil
+	}
+	file.AddImport("", "regexp")
+	file.P("var (")
+	for _, regexp := range regexps {
+		file.P(regexp)
+	}
+	file.P(")")
+	file.P("")
+	return nil
+}
+
 func ValidatingMiddlewareStruct(file file.File, service types.Service) error {
 	file.AddImport("", service.ImportPath, "/pkg/service/handlers")
 	file.P("type validatingMiddleware struct {")
@@ -73,6 +99,7 @@ func ValidatingMiddlewareMethodFunc(file file.File, service types.Service, metho
 	}
 	file.Pf("func (m *validatingMiddleware) %s(%s) (%s) {", methodName, strs.Join(args, ", "), strs.Join(results, ", "))
 	if method.Generate.Has(constants.MethodGenerateMiddlewareFlag, constants.MethodGenerateValidatingFlag) {
+		ArgumentValidators(file, method)
 		file.Pf("if m.%sValidator != nil {", lowerMethodName)
 		file.Pf("err = m.%sValidator(ctx, req)", lowerMethodName)
 		file.Pf("if err != nil {")
@@ -89,3 +116,91 @@ func ValidatingMiddlewareMethodFunc(file file.File, service types.Service, metho
 	file.Pf("")
 	return nil
 }
+
+func ArgumentValidators(file file.File, method types.Method) {
+	var hasValidators bool
+	for _, arg := range method.Arguments {
+		hasValidators = hasValidators || len(arg.Options.Validators) > 0
+	}
+	if !hasValidators {
+		return
+	}
+	file.AddImport("", "github.com/wlMalk/goms/goms/errors")
+	file.Pf("verr := &errors.ErrValidation{}")
+	for _, arg := range method.Arguments {
+		name := helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias)
+		for i, validator := range arg.Options.Validators {
+			condition, message := ArgumentValidatorCondition(file, method, arg, i, validator)
+			if i == 0 {
+				file.Pf("if %s {", condition)
+			} else {
+				file.Pf("} else if %s {", condition)
+			}
+			file.Pf("verr.Add(%s, %s, %s)", strconv.Quote(name), strconv.Quote(validator.Name), strconv.Quote(message))
+		}
+		if len(arg.Options.Validators) > 0 {
+			file.Pf("}")
+		}
+	}
+	file.Pf("if len(verr.Violations) > 0 {")
+	file.Pf("err = verr")
+	file.Pf("return")
+	file.Pf("}")
+}
+
+func ArgumentValidatorCondition(file file.File, method types.Method, arg *types.Argument, index int, validator types.Validator) (condition string, message string) {
+	field := "req." + strings.ToUpperFirst(arg.Name)
+	name := helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias)
+	isString := arg.Type.IsBuiltin && arg.Type.Name == "string" && !arg.Type.IsSlice && !arg.Type.IsVariadic && !arg.Type.IsMap
+	length := "len(" + field + ")"
+	if isString {
+		file.AddImport("", "unicode/utf8")
+		length = "utf8.RuneCountInString(" + field + ")"
+	}
+	switch validator.Name {
+	case constants.ValidatorRequired:
+		if isString {
+			return field + " == \"\"", fmt.Sprintf("'%s' is required", name)
+		}
+		return field + " == nil", fmt.Sprintf("'%s' is required", name)
+	case constants.ValidatorNonEmpty:
+		return "len(" + field + ") == 0", fmt.Sprintf("'%s' must not be empty", name)
+	case constants.ValidatorMinLen:
+		return length + " < " + validator.Args[0], fmt.Sprintf("'%s' length must be at least %s", name, validator.Args[0])
+	case constants.ValidatorMaxLen:
+		return length + " > " + validator.Args[0], fmt.Sprintf("'%s' length must be at most %s", name, validator.Args[0])
+	case constants.ValidatorRange:
+		return fmt.Sprintf("%s < %s || %s > %s", field, validator.Args[0], field, validator.Args[1]),
+			fmt.Sprintf("'%s' must be between %s and %s", name, validator.Args[0], validator.Args[1])
+	case constants.ValidatorRegex:
+		return fmt.Sprintf("!%s.MatchString(%s)", validatorRegexpName(method, arg, index), field),
+			fmt.Sprintf("'%s' must match '%s'", name, validator.Args[0])
+	case constants.ValidatorEnum:
+		var conditions, names []string
+		if arg.Type.IsEnum {
+			if arg.Type.IsPointer {
+				conditions = append(conditions, field+" != nil")
+				field = "*" + field
+			}
+			for _, c := range arg.Type.Enum.Cases {
+				conditions = append(conditions, field+" != types."+strs.ToUpper(strings.ToSnakeCase(c.Name)))
+				names = append(names, c.Name)
+			}
+		} else {
+			for _, v := range validator.Args {
+				if isString {
+					conditions = append(conditions, field+" != "+strconv.Quote(v))
+				} else {
+					conditions = append(conditions, field+" != "+v)
+				}
+				names = append(names, v)
+			}
+		}
+		return strs.Join(conditions, " && "), fmt.Sprintf("'%s' must be one of %s", name, strs.Join(names, ", "))
+	}
+	return "false", ""
+}
+
+func validatorRegexpName(method types.Method, arg *types.Argument, index int) string {
+	return fmt.Sprintf("%s%sRegexp%d", strings.ToLowerFirst(method.Name), strings.ToUpperFirst(arg.Name), index)
+}
@@ -40,6 +40,7 @@ func GRPCTransportClientNewSpecialFunc(file file.File, service types.Service) er
 	serviceNameSnake := strings.ToSnakeCase(service.Name)
 	file.AddImport("kit_grpc", "github.com/go-kit/kit/transport/grpc")
 	file.AddImport("", "google.golang.org/grpc")
+	file.AddImport("goms_grpc", "github.com/wlMalk/goms/goms/transport/grpc")
 	file.Pf("func NewSpecial(conn *grpc.ClientConn, optionsFunc func(method string) (opts []kit_grpc.ClientOption)) *Client {")
 	file.Pf("return &Client{")
 	for _, method := range helpers.GetMethodsWithGRPCClientEnabled(service) {
@@ -49,7 +50,11 @@ func GRPCTransportClientNewSpecialFunc(file file.File, service types.Service) er
 		lowerMethodName := strings.ToLowerFirst(method.Name)
 		file.Pf("%s: converters.%sRequestResponseHandlerTo%sHandler(", lowerMethodName, methodName, methodName)
 		file.Pf("converters.EndpointTo%sRequestResponseHandler(", methodName)
-		file.Pf("kit_grpc.NewClient(")
+		if method.IsStreaming() {
+			file.Pf("goms_grpc.ErrorDecoder(new%sStreamEndpoint(conn, optionsFunc(\"%s\")...)))),", methodName, helpers.GetName(methodName, method.Alias))
+			continue
+		}
+		file.Pf("goms_grpc.ErrorDecoder(kit_grpc.NewClient(")
 		file.Pf("conn, \"%s.%sService\", \"%s\",", serviceNameSnake, serviceName, methodName)
 		file.Pf("%s_grpc.Encode%sRequest,", serviceNameSnake, methodName)
 		file.Pf("%s_grpc.Decode%sResponse,", serviceNameSnake, methodName)
@@ -61,7 +66,7 @@ func GRPCTransportClientNewSpecialFunc(file file.File, service types.Service) er
 			file.Pf("empty.Empty{},")
 		}
 		file.Pf("optionsFunc(\"%s\")...,", helpers.GetName(methodName, method.Alias))
-		file.Pf(").Endpoint())),")
+		file.Pf(").Endpoint()))),")
 	}
 	file.Pf("}")
 	file.Pf("}")
@@ -82,6 +87,135 @@ func GRPCTransportClientMethodFunc(file file.File, service types.Service, method
 	return nil
 }
 
+// GRPCTransportClientStreamEndpointFunc generates the endpoint calling a
+// streaming method, moving the values between its channels and the gRPC
+// stream. Errors ending the stream after the call returned close the channel.
+// The request and response functions of the client options are applied to
+// the metadata of the call.
+func GRPCTransportClientStreamEndpointFunc(file file.File, service types.Service, method types.Method) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	methodName := strings.ToUpperFirst(method.Name)
+	file.AddImport("", "context")
+	file.AddImport("", "google.golang.org/grpc")
+	file.AddImport("", "github.com/go-kit/kit/endpoint")
+	file.AddImport("kit_grpc", "github.com/go-kit/kit/transport/grpc")
+	file.AddImport("goms_grpc", "github.com/wlMalk/goms/goms/transport/grpc")
+	file.AddImport("pb", service.ImportPath, "/pkg/protobuf/", strings.ToLower(strings.ToSnakeCase(service.Name)))
+	file.Pf("func new%sStreamEndpoint(conn *grpc.ClientConn, opts ...kit_grpc.ClientOption) endpoint.Endpoint {", methodName)
+	file.Pf("client := pb.New%sServiceClient(conn)", serviceName)
+	file.Pf("c := goms_grpc.NewStreamClient(opts...)")
+	file.Pf("return func(ctx context.Context, request interface{}) (interface{}, error) {")
+	file.Pf("ctx = c.Before(ctx)")
+	if method.IsClientStreaming() {
+		file.AddImport("", service.ImportPath, "/pkg/service/requests")
+		arg := method.Arguments[0]
+		argName := strings.ToUpperFirst(arg.Name)
+		file.Pf("stream, err := client.%s(ctx)", methodName)
+		file.Pf("if err != nil {")
+		file.Pf("return nil, err")
+		file.Pf("}")
+		file.Pf("in := request.(*requests.%sRequest).%s", methodName, argName)
+		send := func(ret string) {
+			file.Pf("for {")
+			file.Pf("var v %s", arg.Type.Elem().GoType())
+			file.Pf("var ok bool")
+			file.Pf("select {")
+			file.Pf("case v, ok = <-in:")
+			file.Pf("case <-ctx.Done():")
+			file.Pf("return %s", ret)
+			file.Pf("}")
+			file.Pf("if !ok {")
+			file.Pf("break")
+			file.Pf("}")
+			file.Pf("req := &pb.%sRequest{}", methodName)
+			ProtoConverter(file, service, arg.Type.Elem(), "req."+argName, "v", true, true)
+			file.Pf("if err := stream.Send(req); err != nil {")
+			file.Pf("return %s", strs.Replace(ret, "ctx.Err()", "err", 1))
+			file.Pf("}")
+			file.Pf("}")
+		}
+		if !method.IsServerStreaming() {
+			send("nil, ctx.Err()")
+			file.Pf("res, err := stream.CloseAndRecv()")
+			file.Pf("if err != nil {")
+			file.Pf("return nil, err")
+			file.Pf("}")
+			file.Pf("if md, err := stream.Header(); err == nil {")
+			file.Pf("c.After(ctx, md, stream.Trailer())")
+			file.Pf("}")
+			if len(method.Results) > 0 {
+				file.AddImport("pb_responses", service.ImportPath, "pkg/protobuf", strings.ToLower(strings.ToSnakeCase(service.Name)), "responses")
+				file.Pf("return pb_responses.%sFromProto(res)", methodName)
+			} else {
+				file.Pf("_ = res")
+				file.Pf("return nil, nil")
+			}
+			file.Pf("}")
+			file.Pf("}")
+			file.Pf("")
+			return nil
+		}
+		file.Pf("go func() {")
+		file.Pf("defer stream.CloseSend()")
+		send("")
+		file.Pf("}()")
+	} else if len(method.Arguments) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/requests")
+		file.AddImport("pb_requests", service.ImportPath, "pkg/protobuf", strings.ToLower(strings.ToSnakeCase(service.Name)), "requests")
+		file.Pf("req, err := pb_requests.%s(request.(*requests.%sRequest))", methodName, methodName)
+		file.Pf("if err != nil {")
+		file.Pf("return nil, err")
+		file.Pf("}")
+		file.Pf("stream, err := client.%s(ctx, req)", methodName)
+	} else {
+		file.AddImport("empty", "github.com/golang/protobuf/ptypes/empty")
+		file.Pf("stream, err := client.%s(ctx, &empty.Empty{})", methodName)
+	}
+	if !method.IsClientStreaming() {
+		file.Pf("if err != nil {")
+		file.Pf("return nil, err")
+		file.Pf("}")
+	}
+	file.AddImport("", service.ImportPath, "/pkg/service/responses")
+	result := method.Results[0]
+	resultName := strings.ToUpperFirst(result.Name)
+	// the server sends the headers once the call succeeded, so that its
+	// errors are returned before the stream is handed out
+	file.AddImport("", "io")
+	file.Pf("md, err := stream.Header()")
+	file.Pf("if err != nil {")
+	file.Pf("return nil, err")
+	file.Pf("}")
+	file.Pf("if md == nil {")
+	file.Pf("if _, err := stream.Recv(); err != io.EOF {")
+	file.Pf("return nil, err")
+	file.Pf("}")
+	file.Pf("}")
+	file.Pf("ctx = c.After(ctx, md, nil)")
+	file.Pf("out := make(chan %s)", result.Type.Elem().GoType())
+	file.Pf("go func() {")
+	file.Pf("defer close(out)")
+	file.Pf("for {")
+	file.Pf("res, err := stream.Recv()")
+	file.Pf("if err != nil {")
+	file.Pf("return")
+	file.Pf("}")
+	file.Pf("var v %s", result.Type.Elem().GoType())
+	ProtoConverter(file, service, result.Type.Elem(), "v", "res."+resultName, false, true)
+	file.Pf("select {")
+	file.Pf("case out <- v:")
+	file.Pf("case <-ctx.Done():")
+	file.Pf("return")
+	file.Pf("}")
+	file.Pf("}")
+	file.Pf("}()")
+	file.Pf("return &responses.%sResponse{%s: out}, nil", methodName, resultName)
+	file.Pf("}")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
 func GRPCTransportClientGlobalVar(file file.File, service types.Service) error {
 	file.AddImport("", service.ImportPath, "/pkg/transport/grpc/client")
 	file.Pf("var c *client.Client = client.New(nil)")
@@ -1,6 +1,8 @@
 package generators
 
 import (
+	strs "strings"
+
 	"github.com/wlMalk/goms/generator/file"
 	"github.com/wlMalk/goms/generator/helpers"
 	"github.com/wlMalk/goms/generator/strings"
@@ -38,8 +40,13 @@ func GRPCTransportServerRegisterSpecialFunc(file file.File, service types.Servic
 		lowerMethodName := strings.ToLowerFirst(method.Name)
 		file.Pf("%s: kit_grpc.NewServer(", lowerMethodName)
 		file.Pf("endpoints.%s,", methodName)
-		file.Pf("%s_grpc.Decode%sRequest,", serviceNameSnake, methodName)
-		file.Pf("%s_grpc.Encode%sResponse,", serviceNameSnake, methodName)
+		if method.IsStreaming() {
+			file.Pf("goms_grpc.NopRequestDecoder,")
+			file.Pf("goms_grpc.NopResponseEncoder,")
+		} else {
+			file.Pf("%s_grpc.Decode%sRequest,", serviceNameSnake, methodName)
+			file.Pf("%s_grpc.Encode%sResponse,", serviceNameSnake, methodName)
+		}
 		file.Pf("optionsFunc(\"%s\")...),", helpers.GetName(methodName, method.Alias))
 	}
 	file.Pf("}")
@@ -61,9 +68,13 @@ func GRPCTransportServerHandlerStruct(file file.File, service types.Service) err
 }
 
 func GRPCTransportServerHandlerMethodFunc(file file.File, service types.Service, method types.Method) error {
+	if method.IsStreaming() {
+		return grpcTransportServerStreamHandlerMethodFunc(file, service, method)
+	}
 	methodName := strings.ToUpperFirst(method.Name)
 	lowerMethodName := strings.ToLowerFirst(method.Name)
 	file.AddImport("", "context")
+	file.AddImport("goms_grpc", "github.com/wlMalk/goms/goms/transport/grpc")
 	if len(method.Arguments) > 0 && len(method.Results) > 0 {
 		file.AddImport("pb", service.ImportPath, "pkg/protobuf", strings.ToLower(strings.ToSnakeCase(service.Name)))
 		file.Pf("func (h *serverHandler) %s(ctx context.Context, req *pb.%sRequest) (*pb.%sResponse, error) {", methodName, methodName, methodName)
@@ -79,7 +90,7 @@ func GRPCTransportServerHandlerMethodFunc(file file.File, service types.Service,
 	}
 	file.Pf("_, resp, err := h.%s.ServeGRPC(ctx, req)", lowerMethodName)
 	file.Pf("if err != nil {")
-	file.Pf("return nil, err")
+	file.Pf("return nil, goms_grpc.EncodeError(err)")
 	file.Pf("}")
 	if len(method.Results) > 0 {
 		file.Pf("return resp.(*pb.%sResponse), nil", methodName)
@@ -90,3 +101,130 @@ func GRPCTransportServerHandlerMethodFunc(file file.File, service types.Service,
 	file.Pf("")
 	return nil
 }
+
+// grpcTransportServerStreamHandlerMethodFunc bridges a gRPC stream and the
+// channels of a streaming method, serving the converted request with the
+// go-kit server of the method so that its options apply.
+func grpcTransportServerStreamHandlerMethodFunc(file file.File, service types.Service, method types.Method) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	methodName := strings.ToUpperFirst(method.Name)
+	lowerMethodName := strings.ToLowerFirst(method.Name)
+	file.AddImport("", "context")
+	file.AddImport("goms_grpc", "github.com/wlMalk/goms/goms/transport/grpc")
+	file.AddImport("pb", service.ImportPath, "/pkg/protobuf/", strings.ToLower(strings.ToSnakeCase(service.Name)))
+	reqType, resType := grpcMessageTypes(file, method)
+	streamType := "pb." + serviceName + "Service_" + methodName + "Server"
+	if method.IsClientStreaming() {
+		file.Pf("func (h *serverHandler) %s(stream %s) error {", methodName, streamType)
+	} else {
+		file.Pf("func (h *serverHandler) %s(req %s, stream %s) error {", methodName, reqType, streamType)
+	}
+	file.Pf("ctx, cancel := context.WithCancel(stream.Context())")
+	file.Pf("defer cancel()")
+	if method.IsClientStreaming() {
+		file.AddImport("", "io")
+		file.AddImport("", service.ImportPath, "/pkg/service/requests")
+		arg := method.Arguments[0]
+		argName := strings.ToUpperFirst(arg.Name)
+		file.Pf("errs := make(chan error, 1)")
+		file.Pf("in := make(chan %s)", arg.Type.Elem().GoType())
+		file.Pf("go func() {")
+		file.Pf("defer close(in)")
+		file.Pf("for {")
+		file.Pf("req, err := stream.Recv()")
+		file.Pf("if err != nil {")
+		file.Pf("if err != io.EOF {")
+		file.Pf("errs <- err")
+		file.Pf("}")
+		file.Pf("return")
+		file.Pf("}")
+		file.Pf("var v %s", arg.Type.Elem().GoType())
+		ProtoConverter(file, service, arg.Type.Elem(), "v", "req."+argName, false, true)
+		file.Pf("select {")
+		file.Pf("case in <- v:")
+		file.Pf("case <-ctx.Done():")
+		file.Pf("return")
+		file.Pf("}")
+		file.Pf("}")
+		file.Pf("}()")
+		file.Pf("_, resp, err := h.%s.ServeGRPC(ctx, &requests.%sRequest{%s: in})", lowerMethodName, methodName, argName)
+	} else if len(method.Arguments) > 0 {
+		file.AddImport("pb_requests", service.ImportPath, "pkg/protobuf", strings.ToLower(strings.ToSnakeCase(service.Name)), "requests")
+		file.Pf("r, err := pb_requests.%sFromProto(req)", methodName)
+		file.Pf("if err != nil {")
+		file.Pf("return goms_grpc.EncodeError(err)")
+		file.Pf("}")
+		file.Pf("_, resp, err := h.%s.ServeGRPC(ctx, r)", lowerMethodName)
+	} else {
+		file.Pf("_, resp, err := h.%s.ServeGRPC(ctx, nil)", lowerMethodName)
+	}
+	file.Pf("if err != nil {")
+	file.Pf("return goms_grpc.EncodeError(err)")
+	file.Pf("}")
+	if method.IsServerStreaming() {
+		file.AddImport("", service.ImportPath, "/pkg/service/responses")
+		result := method.Results[0]
+		resultName := strings.ToUpperFirst(result.Name)
+		file.AddImport("", "google.golang.org/grpc/metadata")
+		// the server may have sent the headers set by its after functions
+		file.Pf("stream.SendHeader(metadata.MD{})")
+		file.Pf("results := resp.(*responses.%sResponse).%s", methodName, resultName)
+		file.Pf("for {")
+		file.Pf("var v %s", result.Type.Elem().GoType())
+		file.Pf("var ok bool")
+		file.Pf("select {")
+		file.Pf("case v, ok = <-results:")
+		file.Pf("case <-ctx.Done():")
+		file.Pf("return ctx.Err()")
+		file.Pf("}")
+		file.Pf("if !ok {")
+		file.Pf("break")
+		file.Pf("}")
+		file.Pf("res := &pb.%sResponse{}", methodName)
+		ProtoConverter(file, service, result.Type.Elem(), "res."+resultName, "v", true, true)
+		file.Pf("if err := stream.Send(res); err != nil {")
+		file.Pf("return err")
+		file.Pf("}")
+		file.Pf("}")
+	}
+	if method.IsClientStreaming() {
+		file.Pf("select {")
+		file.Pf("case err := <-errs:")
+		file.Pf("return err")
+		file.Pf("default:")
+		file.Pf("}")
+	}
+	if method.IsServerStreaming() {
+		file.Pf("return nil")
+	} else if len(method.Results) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/responses")
+		file.AddImport("pb_responses", service.ImportPath, "pkg/protobuf", strings.ToLower(strings.ToSnakeCase(service.Name)), "responses")
+		file.Pf("res, err := pb_responses.%s(resp.(*responses.%sResponse))", methodName, methodName)
+		file.Pf("if err != nil {")
+		file.Pf("return goms_grpc.EncodeError(err)")
+		file.Pf("}")
+		file.Pf("return stream.SendAndClose(res)")
+	} else {
+		file.Pf("return stream.SendAndClose(&%s{})", strs.TrimPrefix(resType, "*"))
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+// grpcMessageTypes returns the Go types of the protobuf request and response
+// messages of the method.
+func grpcMessageTypes(file file.File, method types.Method) (string, string) {
+	methodName := strings.ToUpperFirst(method.Name)
+	req, res := "*pb."+methodName+"Request", "*pb."+methodName+"Response"
+	if len(method.Arguments) == 0 || len(method.Results) == 0 {
+		file.AddImport("empty", "github.com/golang/protobuf/ptypes/empty")
+	}
+	if len(method.Arguments) == 0 {
+		req = "*empty.Empty"
+	}
+	if len(method.Results) == 0 {
+		res = "*empty.Empty"
+	}
+	return req, res
+}
@@ -13,7 +13,7 @@ func HTTPTransportClientStruct(file file.File, service types.Service) error {
 	file.AddImport("", "context")
 	file.AddImport("", service.ImportPath, "/pkg/service/handlers")
 	file.Pf("type Client struct {")
-	for _, method := range service.Methods {
+	for _, method := range helpers.GetMethodsWithHTTPClientEnabled(service) {
 		methodName := strings.ToUpperFirst(method.Name)
 		lowerMethodName := strings.ToLowerFirst(method.Name)
 		file.Pf("%s handlers.%sHandler", lowerMethodName, methodName)
@@ -41,9 +41,10 @@ func HTTPTransportClientNewSpecialFunc(file file.File, service types.Service) er
 	file.AddImport("kit_http", "github.com/go-kit/kit/transport/http")
 	file.AddImport("", service.ImportPath, "/pkg/service/handlers/converters")
 	file.AddImport(serviceNameSnake+"_http", service.ImportPath, "/pkg/transport/http")
+	file.AddImport("goms_http", "github.com/wlMalk/goms/goms/transport/http")
 	file.Pf("func NewSpecial(u *url.URL, optionsFunc func(method string) (opts []kit_http.ClientOption)) *Client {")
 	file.Pf("return &Client{")
-	for _, method := range service.Methods {
+	for _, method := range helpers.GetMethodsWithHTTPClientEnabled(service) {
 		methodName := strings.ToUpperFirst(method.Name)
 		lowerMethodName := strings.ToLowerFirst(method.Name)
 		file.Pf("%s: converters.%sRequestResponseHandlerTo%sHandler(", lowerMethodName, methodName, methodName)
@@ -51,8 +52,15 @@ func HTTPTransportClientNewSpecialFunc(file file.File, service types.Service) er
 		file.Pf("kit_http.NewClient(")
 		file.Pf("\"POST\", u,")
 		file.Pf("%s_http.Encode%sRequest,", serviceNameSnake, methodName)
-		file.Pf("%s_http.Decode%sResponse,", serviceNameSnake, methodName)
-		file.Pf("optionsFunc(\"%s\")...,", helpers.GetName(methodName, method.Alias))
+		file.Pf("goms_http.ErrorDecoder(%s_http.Decode%sResponse),", serviceNameSnake, methodName)
+		switch method.Options.HTTP.Stream {
+		case "sse":
+			file.Pf("append([]kit_http.ClientOption{kit_http.BufferedStream(true)}, optionsFunc(\"%s\")...)...,", helpers.GetName(methodName, method.Alias))
+		case "ws":
+			file.Pf("append([]kit_http.ClientOption{kit_http.BufferedStream(true), kit_http.SetClient(goms_http.WebSocketClient(nil))}, optionsFunc(\"%s\")...)...,", helpers.GetName(methodName, method.Alias))
+		default:
+			file.Pf("optionsFunc(\"%s\")...,", helpers.GetName(methodName, method.Alias))
+		}
 		file.Pf(").Endpoint())),")
 	}
 	file.Pf("}")
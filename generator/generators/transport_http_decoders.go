@@ -31,7 +31,29 @@ func HTTPResponseDecoder(file file.File, service types.Service, method types.Met
 	file.AddImport("", "net/http")
 	methodName := strings.ToUpperFirst(method.Name)
 	file.Pf("func Decode%sResponse(ctx context.Context, res *http.Response) (interface{}, error) {", methodName)
-	if len(method.Results) > 0 {
+	if method.IsServerStreaming() {
+		result := method.Results[0]
+		file.AddImport("goms_http", "github.com/wlMalk/goms/goms/transport/http")
+		file.AddImport("service_responses", service.ImportPath, "/pkg/service/responses")
+		file.Pf("stream := goms_http.NewStreamReader(ctx, res)")
+		file.Pf("results := make(chan %s)", result.Type.Elem().GoType())
+		file.Pf("go func() {")
+		file.Pf("defer close(results)")
+		file.Pf("defer stream.Close()")
+		file.Pf("for {")
+		file.Pf("var v %s", result.Type.Elem().GoType())
+		file.Pf("if err := stream.Recv(&v); err != nil {")
+		file.Pf("return")
+		file.Pf("}")
+		file.Pf("select {")
+		file.Pf("case results <- v:")
+		file.Pf("case <-ctx.Done():")
+		file.Pf("return")
+		file.Pf("}")
+		file.Pf("}")
+		file.Pf("}()")
+		file.Pf("return &service_responses.%sResponse{%s: results}, nil", methodName, strings.ToUpperFirst(result.Name))
+	} else if len(method.Results) > 0 {
 		file.AddImport("", service.ImportPath, "/pkg/transport/http/responses")
 		file.Pf("resp, err := responses.%sFromHTTP(res)", methodName)
 		file.Pf("if err!=nil{")
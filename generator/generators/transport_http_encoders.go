@@ -40,7 +40,33 @@ func HTTPResponseEncoder(file file.File, service types.Service, method types.Met
 	serviceName := strings.ToUpperFirst(service.Name)
 	methodName := strings.ToUpperFirst(method.Name)
 	file.Pf("func Encode%sResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {", methodName)
-	if len(method.Results) > 0 {
+	if method.IsServerStreaming() {
+		resultName := strings.ToUpperFirst(method.Results[0].Name)
+		file.AddImport("goms_http", "github.com/wlMalk/goms/goms/transport/http")
+		file.AddImport("", service.ImportPath, "/pkg/service/responses")
+		file.AddImport("", "github.com/wlMalk/goms/goms/errors")
+		file.Pf("if response == nil {")
+		file.Pf("return errors.InvalidResponse(\"%s\", \"%s\")", helpers.GetName(serviceName, service.Alias), helpers.GetName(methodName, method.Alias))
+		file.Pf("}")
+		file.Pf("stream, err := goms_http.NewStreamWriter(ctx, w)")
+		file.Pf("if err != nil {")
+		file.Pf("return err")
+		file.Pf("}")
+		file.Pf("results := response.(*responses.%sResponse).%s", methodName, resultName)
+		file.Pf("for {")
+		file.Pf("select {")
+		file.Pf("case v, ok := <-results:")
+		file.Pf("if !ok {")
+		file.Pf("return nil")
+		file.Pf("}")
+		file.Pf("if err := stream.Send(v); err != nil {")
+		file.Pf("return err")
+		file.Pf("}")
+		file.Pf("case <-ctx.Done():")
+		file.Pf("return nil")
+		file.Pf("}")
+		file.Pf("}")
+	} else if len(method.Results) > 0 {
 		file.AddImport("goms_http", "github.com/wlMalk/goms/goms/transport/http")
 		file.AddImport("http_responses", service.ImportPath, "/pkg/transport/http/responses")
 		file.AddImport("", service.ImportPath, "/pkg/service/responses")
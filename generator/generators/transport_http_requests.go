@@ -74,6 +74,9 @@ func HTTPRequestNewFunc(file file.File, service types.Service, method types.Meth
 	file.AddImport("", service.ImportPath, "/pkg/service/requests")
 	file.Pf("func %s(req *requests.%sRequest) *%sRequest {", methodName, methodName, methodName)
 	file.Pf("r := &%sRequest{}", methodName)
+	if (method.Options.HTTP.Method == "POST" || method.Options.HTTP.Method == "PUT") && hasArgumentsOfOrigin(method.Arguments, "BODY") {
+		file.Pf("r.Body = &%sRequestBody{}", methodName)
+	}
 	for _, arg := range method.Arguments {
 		argName := strings.ToUpperFirst(arg.Name)
 		if arg.Options.HTTP.Origin == "BODY" {
@@ -154,7 +157,7 @@ func HTTPRequestToHTTPArgFunc(file file.File, service types.Service, method type
 			file.Pf("query := req.URL.Query()")
 			for _, arg := range getArgumentsOfOrigin(method.Arguments, "QUERY") {
 				argName := strings.ToUpperFirst(arg.Name)
-				argSpecialName := helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias)
+				argSpecialName := strings.ToSnakeCase(helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias))
 				if arg.Type.IsSlice || arg.Type.IsVariadic {
 					file.Pf("for i := range r.%s {", argName)
 					file.Pf("value, err = goms_util.ToString(r.%s[i])", argName)
@@ -171,6 +174,7 @@ func HTTPRequestToHTTPArgFunc(file file.File, service types.Service, method type
 					file.Pf("query.Add(\"%s\", value)", argSpecialName)
 				}
 			}
+			file.Pf("req.URL.RawQuery = query.Encode()")
 		}
 		if hasArgumentsOfOrigin(method.Arguments, "HEADER") {
 			file.Pf("header := req.Header")
@@ -275,10 +279,10 @@ func QueryExtractor(file file.File, arg *types.Argument) {
 		if arg.Type.IsEntity {
 			file.AddImport("", "encoding/json")
 			if arg.Type.IsPointer {
-				file.Pf("vs := *types.%s{}", arg.Type.Entity.Name)
+				file.Pf("vs := &%s{}", arg.Type.NameWithImport())
 				file.Pf("err = json.Unmarshal([]byte(v), vs)")
 			} else {
-				file.Pf("vs := types.%s{}", arg.Type.Entity.Name)
+				file.Pf("vs := %s{}", arg.Type.NameWithImport())
 				file.Pf("err = json.Unmarshal([]byte(v), &vs)")
 			}
 			file.Pf("if err != nil {")
@@ -298,10 +302,10 @@ func QueryExtractor(file file.File, arg *types.Argument) {
 		if arg.Type.IsEntity {
 			file.AddImport("", "encoding/json")
 			if arg.Type.IsPointer {
-				file.Pf("vs := *types.%s{}", arg.Type.Entity.Name)
+				file.Pf("vs := &%s{}", arg.Type.NameWithImport())
 				file.Pf("err = json.Unmarshal([]byte(v), vs)")
 			} else {
-				file.Pf("vs := types.%s{}", arg.Type.Entity.Name)
+				file.Pf("vs := %s{}", arg.Type.NameWithImport())
 				file.Pf("err = json.Unmarshal([]byte(v), &vs)")
 			}
 			file.Pf("if err != nil {")
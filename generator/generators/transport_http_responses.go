@@ -8,7 +8,7 @@ import (
 )
 
 func HTTPResponse(file file.File, service types.Service, method types.Method) error {
-	if len(method.Results) == 0 {
+	if len(method.Results) == 0 || method.IsServerStreaming() {
 		return nil
 	}
 	methodName := strings.ToUpperFirst(method.Name)
@@ -28,7 +28,7 @@ func HTTPResponseFields(file file.File, fields []*types.Field) {
 }
 
 func HTTPResponseNewFunc(file file.File, service types.Service, method types.Method) error {
-	if len(method.Results) == 0 {
+	if len(method.Results) == 0 || method.IsServerStreaming() {
 		return nil
 	}
 	methodName := strings.ToUpperFirst(method.Name)
@@ -46,7 +46,7 @@ func HTTPResponseNewFunc(file file.File, service types.Service, method types.Met
 }
 
 func HTTPResponseNewHTTPFunc(file file.File, service types.Service, method types.Method) error {
-	if len(method.Results) == 0 {
+	if len(method.Results) == 0 || method.IsServerStreaming() {
 		return nil
 	}
 	file.AddImport("", "net/http")
@@ -65,7 +65,7 @@ func HTTPResponseNewHTTPFunc(file file.File, service types.Service, method types
 }
 
 func HTTPResponseToResponseFunc(file file.File, service types.Service, method types.Method) error {
-	if len(method.Results) == 0 {
+	if len(method.Results) == 0 || method.IsServerStreaming() {
 		return nil
 	}
 	file.AddImport("", service.ImportPath, "/pkg/service/responses")
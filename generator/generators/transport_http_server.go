@@ -35,20 +35,40 @@ func HTTPTransportServerRegisterSpecialFunc(file file.File, service types.Servic
 	file.Pf("func RegisterSpecial(server *goms_http.Server, endpoints *transport.%s, optionsFunc func(method string) (opts []kit_http.ServerOption)) {", serviceName)
 	for _, method := range helpers.GetMethodsWithHTTPServerEnabled(service) {
 		methodName := strings.ToUpperFirst(method.Name)
-		methodHTTPMethod := method.Options.HTTP.Method
+		methodHTTPMethod := getMethodHTTPMethod(method)
 		methodURI := getMethodURI(service, method)
-		file.Pf("server.RegisterMethod(\"%s\", \"%s\", kit_http.NewServer(", methodHTTPMethod, methodURI)
+		switch method.Options.HTTP.Stream {
+		case "sse":
+			file.Pf("server.RegisterMethod(\"%s\", \"%s\", goms_http.ServerSentEvents(kit_http.NewServer(", methodHTTPMethod, methodURI)
+		case "ws":
+			file.Pf("server.RegisterMethod(\"%s\", \"%s\", goms_http.WebSocket(kit_http.NewServer(", methodHTTPMethod, methodURI)
+		default:
+			file.Pf("server.RegisterMethod(\"%s\", \"%s\", kit_http.NewServer(", methodHTTPMethod, methodURI)
+		}
 		file.Pf("endpoints.%s,", methodName)
 		file.Pf("%s_http.Decode%sRequest,", serviceNameSnake, methodName)
 		file.Pf("%s_http.Encode%sResponse,", serviceNameSnake, methodName)
-		file.Pf("optionsFunc(\"%s\")...),", helpers.GetName(methodName, method.Alias))
-		file.Pf(")")
+		file.Pf("append([]kit_http.ServerOption{kit_http.ServerErrorEncoder(goms_http.ErrorEncoder)}, optionsFunc(\"%s\")...)...),", helpers.GetName(methodName, method.Alias))
+		if method.Options.HTTP.Stream != "" {
+			file.Pf("))")
+		} else {
+			file.Pf(")")
+		}
 	}
 	file.Pf("}")
 	file.Pf("")
 	return nil
 }
 
+// getMethodHTTPMethod returns the HTTP method the method is served with,
+// WebSocket streams are always opened with GET.
+func getMethodHTTPMethod(method types.Method) string {
+	if method.Options.HTTP.Stream == "ws" {
+		return "GET"
+	}
+	return method.Options.HTTP.Method
+}
+
 func getMethodURI(service types.Service, method types.Method) string {
 	serviceNameSnake := strings.ToSnakeCase(service.Name)
 	serviceVersion := "v" + service.Version.String()
@@ -0,0 +1,98 @@
+package generators
+
+import (
+	strs "strings"
+
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func JSONRPCTransportClientStruct(file file.File, service types.Service) error {
+	file.AddImport("", "context")
+	file.AddImport("", service.ImportPath, "/pkg/service/handlers")
+	file.Pf("type Client struct {")
+	for _, method := range helpers.GetMethodsWithJSONRPCClientEnabled(service) {
+		methodName := strings.ToUpperFirst(method.Name)
+		lowerMethodName := strings.ToLowerFirst(method.Name)
+		file.Pf("%s handlers.%sHandler", lowerMethodName, methodName)
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func JSONRPCTransportClientNewFunc(file file.File, service types.Service) error {
+	file.AddImport("", "net/url")
+	file.AddImport("kit_jsonrpc", "github.com/go-kit/kit/transport/http/jsonrpc")
+	file.Pf("func New(u *url.URL, opts ...kit_jsonrpc.ClientOption) *Client {")
+	file.Pf("return NewSpecial(u, func(_ string) []kit_jsonrpc.ClientOption {")
+	file.Pf("return opts")
+	file.Pf("})")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func JSONRPCTransportClientNewSpecialFunc(file file.File, service types.Service) error {
+	serviceNameSnake := strings.ToSnakeCase(service.Name)
+	file.AddImport("", "net/url")
+	file.AddImport("kit_jsonrpc", "github.com/go-kit/kit/transport/http/jsonrpc")
+	file.AddImport("", service.ImportPath, "/pkg/service/handlers/converters")
+	file.AddImport(serviceNameSnake+"_jsonrpc", service.ImportPath, "/pkg/transport/jsonrpc")
+	file.AddImport("goms_jsonrpc", "github.com/wlMalk/goms/goms/transport/jsonrpc")
+	file.Pf("func NewSpecial(u *url.URL, optionsFunc func(method string) (opts []kit_jsonrpc.ClientOption)) *Client {")
+	file.Pf("return &Client{")
+	for _, method := range helpers.GetMethodsWithJSONRPCClientEnabled(service) {
+		methodName := strings.ToUpperFirst(method.Name)
+		lowerMethodName := strings.ToLowerFirst(method.Name)
+		rpcName := helpers.GetName(methodName, method.Alias)
+		file.Pf("%s: converters.%sRequestResponseHandlerTo%sHandler(", lowerMethodName, methodName, methodName)
+		file.Pf("converters.EndpointTo%sRequestResponseHandler(", methodName)
+		file.Pf("kit_jsonrpc.NewClient(")
+		file.Pf("u, \"%s\",", rpcName)
+		file.Pf("append([]kit_jsonrpc.ClientOption{")
+		file.Pf("kit_jsonrpc.ClientRequestEncoder(%s_jsonrpc.Encode%sRequest),", serviceNameSnake, methodName)
+		file.Pf("kit_jsonrpc.ClientResponseDecoder(goms_jsonrpc.ErrorDecoder(%s_jsonrpc.Decode%sResponse)),", serviceNameSnake, methodName)
+		file.Pf("}, optionsFunc(\"%s\")...)...,", rpcName)
+		file.Pf(").Endpoint())),")
+	}
+	file.Pf("}")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func JSONRPCTransportClientMethodFunc(file file.File, service types.Service, method types.Method) error {
+	methodName := strings.ToUpperFirst(method.Name)
+	lowerMethodName := strings.ToLowerFirst(method.Name)
+	args := append([]string{"ctx context.Context"}, helpers.GetMethodArguments(method.Arguments)...)
+	results := append(helpers.GetMethodResults(method.Results), "err error")
+	argsInCall := append([]string{"ctx"}, helpers.GetMethodArgumentsInCall(method.Arguments)...)
+	file.Pf("func (c *Client) %s(%s) (%s) {", methodName, strs.Join(args, ", "), strs.Join(results, ", "))
+	file.Pf("return c.%s.%s(%s)", lowerMethodName, methodName, strs.Join(argsInCall, ", "))
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func JSONRPCTransportClientGlobalVar(file file.File, service types.Service) error {
+	file.AddImport("", service.ImportPath, "/pkg/transport/jsonrpc/client")
+	file.Pf("var c *client.Client = client.New(nil)")
+	file.Pf("")
+	return nil
+}
+
+func JSONRPCTransportClientGlobalFunc(file file.File, service types.Service, method types.Method) error {
+	methodName := strings.ToUpperFirst(method.Name)
+	file.AddImport("", "context")
+	args := append([]string{"ctx context.Context"}, helpers.GetMethodArguments(method.Arguments)...)
+	results := append(helpers.GetMethodResults(method.Results), "err error")
+	argsInCall := append([]string{"ctx"}, helpers.GetMethodArgumentsInCall(method.Arguments)...)
+	file.Pf("func %s(%s) (%s) {", methodName, strs.Join(args, ", "), strs.Join(results, ", "))
+	file.Pf("return c.%s(%s)", methodName, strs.Join(argsInCall, ", "))
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
@@ -0,0 +1,59 @@
+package generators
+
+import (
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func JSONRPCRequestDecoder(file file.File, service types.Service, method types.Method) error {
+	file.AddImport("", "context")
+	file.AddImport("", "encoding/json")
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("func Decode%sRequest(ctx context.Context, msg json.RawMessage) (interface{}, error) {", methodName)
+	if len(method.Arguments) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/requests")
+		file.AddImport("goms_jsonrpc", "github.com/wlMalk/goms/goms/transport/jsonrpc")
+		file.Pf("params := &%sParams{}", methodName)
+		file.Pf("if err := goms_jsonrpc.DecodeParams(msg, params); err != nil {")
+		file.Pf("return nil, err")
+		file.Pf("}")
+		file.Pf("req := &requests.%sRequest{}", methodName)
+		for _, arg := range method.Arguments {
+			argName := strings.ToUpperFirst(arg.Name)
+			file.Pf("req.%s = params.%s", argName, argName)
+		}
+		file.Pf("return req, nil")
+	} else {
+		file.Pf("return nil, nil")
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func JSONRPCResponseDecoder(file file.File, service types.Service, method types.Method) error {
+	file.AddImport("", "context")
+	file.AddImport("kit_jsonrpc", "github.com/go-kit/kit/transport/http/jsonrpc")
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("func Decode%sResponse(ctx context.Context, res kit_jsonrpc.Response) (interface{}, error) {", methodName)
+	if len(method.Results) > 0 {
+		file.AddImport("", "encoding/json")
+		file.AddImport("", service.ImportPath, "/pkg/service/responses")
+		file.Pf("result := &%sResult{}", methodName)
+		file.Pf("if err := json.Unmarshal(res.Result, result); err != nil {")
+		file.Pf("return nil, err")
+		file.Pf("}")
+		file.Pf("resp := &responses.%sResponse{}", methodName)
+		for _, res := range method.Results {
+			resName := strings.ToUpperFirst(res.Name)
+			file.Pf("resp.%s = result.%s", resName, resName)
+		}
+		file.Pf("return resp, nil")
+	} else {
+		file.Pf("return nil, nil")
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
@@ -0,0 +1,43 @@
+package generators
+
+import (
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func JSONRPCTransportServerRegisterFunc(file file.File, service types.Service) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	file.AddImport("", service.ImportPath, "/pkg/transport")
+	file.AddImport("goms_jsonrpc", "github.com/wlMalk/goms/goms/transport/jsonrpc")
+	file.Pf("func Register(server *goms_jsonrpc.Server, endpoints *transport.%s, opts ...goms_jsonrpc.ServerOption) {", serviceName)
+	file.Pf("RegisterSpecial(server, endpoints, func(_ string) []goms_jsonrpc.ServerOption {")
+	file.Pf("return opts")
+	file.Pf("})")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func JSONRPCTransportServerRegisterSpecialFunc(file file.File, service types.Service) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	serviceNameSnake := strings.ToSnakeCase(service.Name)
+	file.AddImport("kit_jsonrpc", "github.com/go-kit/kit/transport/http/jsonrpc")
+	file.AddImport("", service.ImportPath, "/pkg/transport")
+	file.AddImport(serviceNameSnake+"_jsonrpc", service.ImportPath, "/pkg/transport/jsonrpc")
+	file.AddImport("goms_jsonrpc", "github.com/wlMalk/goms/goms/transport/jsonrpc")
+	file.Pf("func RegisterSpecial(server *goms_jsonrpc.Server, endpoints *transport.%s, optionsFunc func(method string) (opts []goms_jsonrpc.ServerOption)) {", serviceName)
+	for _, method := range helpers.GetMethodsWithJSONRPCServerEnabled(service) {
+		methodName := strings.ToUpperFirst(method.Name)
+		rpcName := helpers.GetName(methodName, method.Alias)
+		file.Pf("server.RegisterMethod(\"%s\", kit_jsonrpc.EndpointCodec{", rpcName)
+		file.Pf("Endpoint: endpoints.%s,", methodName)
+		file.Pf("Decode:   %s_jsonrpc.Decode%sRequest,", serviceNameSnake, methodName)
+		file.Pf("Encode:   %s_jsonrpc.Encode%sResponse,", serviceNameSnake, methodName)
+		file.Pf("}, optionsFunc(\"%s\")...)", rpcName)
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
@@ -0,0 +1,94 @@
+package generators
+
+import (
+	strs "strings"
+
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func MQTransportClientStruct(file file.File, service types.Service) error {
+	file.AddImport("", "context")
+	file.AddImport("", service.ImportPath, "/pkg/service/handlers")
+	file.Pf("type Client struct {")
+	for _, method := range helpers.GetMethodsWithMQClientEnabled(service) {
+		methodName := strings.ToUpperFirst(method.Name)
+		lowerMethodName := strings.ToLowerFirst(method.Name)
+		file.Pf("%s handlers.%sHandler", lowerMethodName, methodName)
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQTransportClientNewFunc(file file.File, service types.Service) error {
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	file.Pf("func New(broker goms_mq.Broker, opts ...goms_mq.ClientOption) *Client {")
+	file.Pf("return NewSpecial(broker, func(_ string) []goms_mq.ClientOption {")
+	file.Pf("return opts")
+	file.Pf("})")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQTransportClientNewSpecialFunc(file file.File, service types.Service) error {
+	serviceNameSnake := strings.ToSnakeCase(service.Name)
+	file.AddImport("", service.ImportPath, "/pkg/service/handlers/converters")
+	file.AddImport(serviceNameSnake+"_mq", service.ImportPath, "/pkg/transport/mq")
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	file.Pf("func NewSpecial(broker goms_mq.Broker, optionsFunc func(method string) (opts []goms_mq.ClientOption)) *Client {")
+	file.Pf("inbox := goms_mq.NewInbox(broker)")
+	file.Pf("return &Client{")
+	for _, method := range helpers.GetMethodsWithMQClientEnabled(service) {
+		methodName := strings.ToUpperFirst(method.Name)
+		lowerMethodName := strings.ToLowerFirst(method.Name)
+		file.Pf("%s: converters.%sRequestResponseHandlerTo%sHandler(", lowerMethodName, methodName, methodName)
+		file.Pf("converters.EndpointTo%sRequestResponseHandler(", methodName)
+		file.Pf("goms_mq.NewClient(")
+		file.Pf("inbox, \"%s\",", getMethodSubject(service, method))
+		file.Pf("%s_mq.Encode%sRequest,", serviceNameSnake, methodName)
+		file.Pf("goms_mq.ErrorDecoder(%s_mq.Decode%sResponse),", serviceNameSnake, methodName)
+		file.Pf("optionsFunc(\"%s\")...,", helpers.GetName(methodName, method.Alias))
+		file.Pf(").Endpoint())),")
+	}
+	file.Pf("}")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQTransportClientMethodFunc(file file.File, service types.Service, method types.Method) error {
+	methodName := strings.ToUpperFirst(method.Name)
+	lowerMethodName := strings.ToLowerFirst(method.Name)
+	args := append([]string{"ctx context.Context"}, helpers.GetMethodArguments(method.Arguments)...)
+	results := append(helpers.GetMethodResults(method.Results), "err error")
+	argsInCall := append([]string{"ctx"}, helpers.GetMethodArgumentsInCall(method.Arguments)...)
+	file.Pf("func (c *Client) %s(%s) (%s) {", methodName, strs.Join(args, ", "), strs.Join(results, ", "))
+	file.Pf("return c.%s.%s(%s)", lowerMethodName, methodName, strs.Join(argsInCall, ", "))
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQTransportClientGlobalVar(file file.File, service types.Service) error {
+	file.AddImport("", service.ImportPath, "/pkg/transport/mq/client")
+	file.Pf("var c *client.Client = client.New(nil)")
+	file.Pf("")
+	return nil
+}
+
+func MQTransportClientGlobalFunc(file file.File, service types.Service, method types.Method) error {
+	methodName := strings.ToUpperFirst(method.Name)
+	file.AddImport("", "context")
+	args := append([]string{"ctx context.Context"}, helpers.GetMethodArguments(method.Arguments)...)
+	results := append(helpers.GetMethodResults(method.Results), "err error")
+	argsInCall := append([]string{"ctx"}, helpers.GetMethodArgumentsInCall(method.Arguments)...)
+	file.Pf("func %s(%s) (%s) {", methodName, strs.Join(args, ", "), strs.Join(results, ", "))
+	file.Pf("return c.%s(%s)", methodName, strs.Join(argsInCall, ", "))
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
@@ -0,0 +1,57 @@
+package generators
+
+import (
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func MQRequestDecoder(file file.File, service types.Service, method types.Method) error {
+	file.AddImport("", "context")
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("func Decode%sRequest(ctx context.Context, msg *goms_mq.Message) (interface{}, error) {", methodName)
+	if len(method.Arguments) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/requests")
+		file.Pf("r := &%sRequest{}", methodName)
+		file.Pf("if err := goms_mq.DecodeJSON(msg, r); err != nil {")
+		file.Pf("return nil, err")
+		file.Pf("}")
+		file.Pf("req := &requests.%sRequest{}", methodName)
+		for _, arg := range method.Arguments {
+			argName := strings.ToUpperFirst(arg.Name)
+			file.Pf("req.%s = r.%s", argName, argName)
+		}
+		file.Pf("return req, nil")
+	} else {
+		file.Pf("return nil, nil")
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQResponseDecoder(file file.File, service types.Service, method types.Method) error {
+	file.AddImport("", "context")
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("func Decode%sResponse(ctx context.Context, msg *goms_mq.Message) (interface{}, error) {", methodName)
+	if len(method.Results) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/responses")
+		file.Pf("r := &%sResponse{}", methodName)
+		file.Pf("if err := goms_mq.DecodeJSON(msg, r); err != nil {")
+		file.Pf("return nil, err")
+		file.Pf("}")
+		file.Pf("resp := &responses.%sResponse{}", methodName)
+		for _, res := range method.Results {
+			resName := strings.ToUpperFirst(res.Name)
+			file.Pf("resp.%s = r.%s", resName, resName)
+		}
+		file.Pf("return resp, nil")
+	} else {
+		file.Pf("return nil, nil")
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
@@ -0,0 +1,62 @@
+package generators
+
+import (
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func MQRequestEncoder(file file.File, service types.Service, method types.Method) error {
+	file.AddImport("", "context")
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	serviceName := strings.ToUpperFirst(service.Name)
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("func Encode%sRequest(ctx context.Context, msg *goms_mq.Message, request interface{}) error {", methodName)
+	if len(method.Arguments) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/requests")
+		file.AddImport("", "github.com/wlMalk/goms/goms/errors")
+		file.Pf("if request == nil {")
+		file.Pf("return errors.InvalidRequest(\"%s\", \"%s\")", helpers.GetName(serviceName, service.Alias), helpers.GetName(methodName, method.Alias))
+		file.Pf("}")
+		file.Pf("req := request.(*requests.%sRequest)", methodName)
+		file.Pf("r := &%sRequest{}", methodName)
+		for _, arg := range method.Arguments {
+			argName := strings.ToUpperFirst(arg.Name)
+			file.Pf("r.%s = req.%s", argName, argName)
+		}
+		file.Pf("return goms_mq.EncodeJSON(ctx, msg, r)")
+	} else {
+		file.Pf("return nil")
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQResponseEncoder(file file.File, service types.Service, method types.Method) error {
+	file.AddImport("", "context")
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	serviceName := strings.ToUpperFirst(service.Name)
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("func Encode%sResponse(ctx context.Context, msg *goms_mq.Message, response interface{}) error {", methodName)
+	if len(method.Results) > 0 {
+		file.AddImport("", service.ImportPath, "/pkg/service/responses")
+		file.AddImport("", "github.com/wlMalk/goms/goms/errors")
+		file.Pf("if response == nil {")
+		file.Pf("return errors.InvalidResponse(\"%s\", \"%s\")", helpers.GetName(serviceName, service.Alias), helpers.GetName(methodName, method.Alias))
+		file.Pf("}")
+		file.Pf("res := response.(*responses.%sResponse)", methodName)
+		file.Pf("r := &%sResponse{}", methodName)
+		for _, res := range method.Results {
+			resName := strings.ToUpperFirst(res.Name)
+			file.Pf("r.%s = res.%s", resName, resName)
+		}
+		file.Pf("return goms_mq.EncodeJSON(ctx, msg, r)")
+	} else {
+		file.Pf("return nil")
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
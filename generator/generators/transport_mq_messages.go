@@ -0,0 +1,38 @@
+package generators
+
+import (
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func MQRequest(file file.File, service types.Service, method types.Method) error {
+	if len(method.Arguments) == 0 {
+		return nil
+	}
+	helpers.AddTypesImports(file, service)
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("type %sRequest struct {", methodName)
+	for _, arg := range method.Arguments {
+		argName := strings.ToUpperFirst(arg.Name)
+		argSpecialName := helpers.GetName(strings.ToLowerFirst(arg.Name), arg.Alias)
+		file.Pf("%s %s `json:\"%s\"`", argName, arg.Type.GoType(), argSpecialName)
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQResponse(file file.File, service types.Service, method types.Method) error {
+	if len(method.Results) == 0 {
+		return nil
+	}
+	helpers.AddTypesImports(file, service)
+	methodName := strings.ToUpperFirst(method.Name)
+	file.Pf("type %sResponse struct {", methodName)
+	HTTPResponseFields(file, method.Results)
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
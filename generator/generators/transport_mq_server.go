@@ -0,0 +1,50 @@
+package generators
+
+import (
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func MQTransportServerRegisterFunc(file file.File, service types.Service) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	file.AddImport("", service.ImportPath, "/pkg/transport")
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	file.Pf("func Register(server *goms_mq.Server, endpoints *transport.%s, opts ...goms_mq.ServerOption) {", serviceName)
+	file.Pf("RegisterSpecial(server, endpoints, func(_ string) []goms_mq.ServerOption {")
+	file.Pf("return opts")
+	file.Pf("})")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func MQTransportServerRegisterSpecialFunc(file file.File, service types.Service) error {
+	serviceName := strings.ToUpperFirst(service.Name)
+	serviceNameSnake := strings.ToSnakeCase(service.Name)
+	file.AddImport("", service.ImportPath, "/pkg/transport")
+	file.AddImport(serviceNameSnake+"_mq", service.ImportPath, "/pkg/transport/mq")
+	file.AddImport("goms_mq", "github.com/wlMalk/goms/goms/transport/mq")
+	file.Pf("func RegisterSpecial(server *goms_mq.Server, endpoints *transport.%s, optionsFunc func(method string) (opts []goms_mq.ServerOption)) {", serviceName)
+	for _, method := range helpers.GetMethodsWithMQServerEnabled(service) {
+		methodName := strings.ToUpperFirst(method.Name)
+		file.Pf("server.RegisterMethod(")
+		file.Pf("\"%s\",", getMethodSubject(service, method))
+		file.Pf("endpoints.%s,", methodName)
+		file.Pf("%s_mq.Decode%sRequest,", serviceNameSnake, methodName)
+		file.Pf("%s_mq.Encode%sResponse,", serviceNameSnake, methodName)
+		file.Pf("optionsFunc(\"%s\")...)", helpers.GetName(methodName, method.Alias))
+	}
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+// getMethodSubject returns the subject the requests to the method are
+// published on.
+func getMethodSubject(service types.Service, method types.Method) string {
+	serviceName := strings.ToUpperFirst(service.Name)
+	methodName := strings.ToUpperFirst(method.Name)
+	return helpers.GetName(serviceName, service.Alias) + "." + helpers.GetName(methodName, method.Alias)
+}
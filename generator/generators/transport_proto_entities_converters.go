@@ -0,0 +1,147 @@
+package generators
+
+import (
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func ProtoEntityNewFunc(file file.File, service types.Service, entity types.Entity) error {
+	entityName := strings.ToUpperFirst(entity.Name)
+	file.AddImport("pb", service.ImportPath, "/pkg/protobuf/", strings.ToLower(strings.ToSnakeCase(service.Name)))
+	file.Pf("func %s(e %s) *pb.%s {", entityName, entityGoType(entity), entityName)
+	file.Pf("p := &pb.%s{}", entityName)
+	for _, field := range entity.Fields {
+		fieldName := strings.ToUpperFirst(field.Name)
+		ProtoConverter(file, service, field.Type, "p."+fieldName, "e."+fieldName, true, false)
+	}
+	file.Pf("return p")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func ProtoEntityNewProtoFunc(file file.File, service types.Service, entity types.Entity) error {
+	entityName := strings.ToUpperFirst(entity.Name)
+	file.AddImport("pb", service.ImportPath, "/pkg/protobuf/", strings.ToLower(strings.ToSnakeCase(service.Name)))
+	file.Pf("func %sFromProto(p *pb.%s) (e %s) {", entityName, entityName, entityGoType(entity))
+	file.Pf("if p == nil {")
+	file.Pf("return")
+	file.Pf("}")
+	for _, field := range entity.Fields {
+		fieldName := strings.ToUpperFirst(field.Name)
+		ProtoConverter(file, service, field.Type, "e."+fieldName, "p."+fieldName, false, false)
+	}
+	file.Pf("return")
+	file.Pf("}")
+	file.Pf("")
+	return nil
+}
+
+func entityGoType(entity types.Entity) string {
+	if entity.IsImport {
+		return entity.Pkg + "." + strings.ToUpperFirst(entity.Name)
+	}
+	return "types." + strings.ToUpperFirst(entity.Name)
+}
+
+func ProtoConverter(file file.File, service types.Service, t *types.Type, to string, from string, toProto bool, qualified bool) {
+	if t.IsMap {
+		key := protoGoType(t.Name, toProto)
+		file.Pf("if %s != nil {", from)
+		file.Pf("%s = make(map[%s]%s, len(%s))", to, key, protoElemType(t.Value, toProto), from)
+		file.Pf("for k, v := range %s {", from)
+		file.Pf("var vv %s", protoElemType(t.Value, toProto))
+		protoElemConverter(file, service, t.Value, "vv", "v", toProto, qualified)
+		file.Pf("%s[%s(k)] = vv", to, key)
+		file.Pf("}")
+		file.Pf("}")
+	} else if (t.IsSlice || t.IsVariadic) && !t.IsBytes {
+		file.Pf("if %s != nil {", from)
+		file.Pf("%s = make([]%s, len(%s))", to, protoElemType(t, toProto), from)
+		file.Pf("for i := range %s {", from)
+		protoElemConverter(file, service, t, to+"[i]", from+"[i]", toProto, qualified)
+		file.Pf("}")
+		file.Pf("}")
+	} else {
+		protoElemConverter(file, service, t, to, from, toProto, qualified)
+	}
+}
+
+func protoElemConverter(file file.File, service types.Service, t *types.Type, to string, from string, toProto bool, qualified bool) {
+	switch {
+	case t.IsEntity:
+		pkg := ""
+		if qualified {
+			file.AddImport("", service.ImportPath, "/pkg/protobuf/", strings.ToLower(strings.ToSnakeCase(service.Name)), "/entities")
+			pkg = "entities."
+		}
+		name := strings.ToUpperFirst(t.Name)
+		if toProto && t.IsPointer {
+			file.Pf("if %s != nil {", from)
+			file.Pf("%s = %s%s(*%s)", to, pkg, name, from)
+			file.Pf("}")
+		} else if toProto {
+			file.Pf("%s = %s%s(%s)", to, pkg, name, from)
+		} else if t.IsPointer {
+			file.Pf("if %s != nil {", from)
+			file.Pf("x := %s%sFromProto(%s)", pkg, name, from)
+			file.Pf("%s = &x", to)
+			file.Pf("}")
+		} else {
+			file.Pf("%s = %s%sFromProto(%s)", to, pkg, name, from)
+		}
+	case t.IsEnum && t.IsPointer:
+		if toProto {
+			file.Pf("if %s != nil {", from)
+			file.Pf("%s = pb.%s(*%s)", to, strings.ToUpperFirst(t.Name), from)
+			file.Pf("}")
+		} else {
+			file.Pf("{")
+			file.Pf("x := %s(%s)", t.NameWithImport(), from)
+			file.Pf("%s = &x", to)
+			file.Pf("}")
+		}
+	case t.IsEnum:
+		if toProto {
+			file.Pf("%s = pb.%s(%s)", to, strings.ToUpperFirst(t.Name), from)
+		} else {
+			file.Pf("%s = %s(%s)", to, t.NameWithImport(), from)
+		}
+	case t.IsBuiltin && !t.IsBytes && protoGoType(t.Name, true) != t.Name:
+		file.Pf("%s = %s(%s)", to, protoGoType(t.Name, toProto), from)
+	default:
+		file.Pf("%s = %s", to, from)
+	}
+}
+
+func protoElemType(t *types.Type, toProto bool) string {
+	switch {
+	case t.IsBytes:
+		return "[]byte"
+	case t.IsEntity && toProto:
+		return "*pb." + strings.ToUpperFirst(t.Name)
+	case t.IsEnum && toProto:
+		return "pb." + strings.ToUpperFirst(t.Name)
+	case t.IsBuiltin:
+		return protoGoType(t.Name, toProto)
+	case t.IsPointer:
+		return "*" + t.NameWithImport()
+	default:
+		return t.NameWithImport()
+	}
+}
+
+func protoGoType(name string, toProto bool) string {
+	if !toProto {
+		return name
+	}
+	switch name {
+	case "int", "int8", "int16":
+		return "int32"
+	case "uint", "uint8", "uint16":
+		return "uint32"
+	default:
+		return name
+	}
+}
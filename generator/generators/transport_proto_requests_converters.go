@@ -7,7 +7,7 @@ import (
 )
 
 func ProtoRequestNewFunc(file file.File, service types.Service, method types.Method) error {
-	if len(method.Arguments) == 0 {
+	if len(method.Arguments) == 0 || method.IsClientStreaming() {
 		return nil
 	}
 	methodName := strings.ToUpperFirst(method.Name)
@@ -17,7 +17,7 @@ func ProtoRequestNewFunc(file file.File, service types.Service, method types.Met
 	file.Pf("req = &pb.%sRequest{}", methodName)
 	for _, arg := range method.Arguments {
 		argName := strings.ToUpperFirst(arg.Name)
-		file.Pf("req.%s = r.%s", argName, argName)
+		ProtoConverter(file, service, arg.Type, "req."+argName, "r."+argName, true, true)
 	}
 	file.Pf("return")
 	file.Pf("}")
@@ -26,7 +26,7 @@ func ProtoRequestNewFunc(file file.File, service types.Service, method types.Met
 }
 
 func ProtoRequestNewProtoFunc(file file.File, service types.Service, method types.Method) error {
-	if len(method.Arguments) == 0 {
+	if len(method.Arguments) == 0 || method.IsClientStreaming() {
 		return nil
 	}
 	methodName := strings.ToUpperFirst(method.Name)
@@ -36,7 +36,7 @@ func ProtoRequestNewProtoFunc(file file.File, service types.Service, method type
 	file.Pf("req = &requests.%sRequest{}", methodName)
 	for _, arg := range method.Arguments {
 		argName := strings.ToUpperFirst(arg.Name)
-		file.Pf("req.%s = r.%s", argName, argName)
+		ProtoConverter(file, service, arg.Type, "req."+argName, "r."+argName, false, true)
 	}
 	file.Pf("return")
 	file.Pf("}")
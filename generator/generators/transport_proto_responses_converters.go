@@ -7,7 +7,7 @@ import (
 )
 
 func ProtoResponseNewFunc(file file.File, service types.Service, method types.Method) error {
-	if len(method.Results) == 0 {
+	if len(method.Results) == 0 || method.IsServerStreaming() {
 		return nil
 	}
 	methodName := strings.ToUpperFirst(method.Name)
@@ -17,7 +17,7 @@ func ProtoResponseNewFunc(file file.File, service types.Service, method types.Me
 	file.Pf("res = &pb.%sResponse{}", methodName)
 	for _, res := range method.Results {
 		resName := strings.ToUpperFirst(res.Name)
-		file.Pf("res.%s = r.%s", resName, resName)
+		ProtoConverter(file, service, res.Type, "res."+resName, "r."+resName, true, true)
 	}
 	file.Pf("return")
 	file.Pf("}")
@@ -26,7 +26,7 @@ func ProtoResponseNewFunc(file file.File, service types.Service, method types.Me
 }
 
 func ProtoResponseNewProtoFunc(file file.File, service types.Service, method types.Method) error {
-	if len(method.Results) == 0 {
+	if len(method.Results) == 0 || method.IsServerStreaming() {
 		return nil
 	}
 	methodName := strings.ToUpperFirst(method.Name)
@@ -36,7 +36,7 @@ func ProtoResponseNewProtoFunc(file file.File, service types.Service, method typ
 	file.Pf("res = &responses.%sResponse{}", methodName)
 	for _, res := range method.Results {
 		resName := strings.ToUpperFirst(res.Name)
-		file.Pf("res.%s = r.%s", resName, resName)
+		ProtoConverter(file, service, res.Type, "res."+resName, "r."+resName, false, true)
 	}
 	file.Pf("return")
 	file.Pf("}")
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"fmt"
+	"path"
 	strs "strings"
 
 	"github.com/wlMalk/goms/constants"
@@ -12,11 +13,68 @@ import (
 )
 
 func AddTypesImports(file file.File, service types.Service) {
-	if len(service.Entities) > 0 || len(service.ArgumentsGroups) > 0 || len(service.Enums) > 0 {
+	if HasTypesDefinitions(service) {
 		file.AddImport("", service.ImportPath, "/pkg/service/types")
 	}
 }
 
+func HasTypesDefinitions(service types.Service) bool {
+	for _, e := range service.Entities {
+		if !e.IsImport {
+			return true
+		}
+	}
+	return len(service.ArgumentsGroups) > 0 || len(service.Enums) > 0
+}
+
+func IsLocalEntity(service types.Service, entity types.Entity) bool {
+	return !entity.IsImport
+}
+
+func GetImportedTypes(service types.Service) (ts []*types.Type) {
+	var add func(t *types.Type)
+	add = func(t *types.Type) {
+		if t == nil {
+			return
+		}
+		if t.IsMap {
+			add(t.Value)
+		} else if t.IsImport {
+			ts = append(ts, t)
+		}
+	}
+	for _, method := range service.Methods {
+		for _, arg := range method.Arguments {
+			add(arg.Type)
+		}
+		for _, result := range method.Results {
+			add(result.Type)
+		}
+	}
+	for _, entity := range service.Entities {
+		for _, field := range entity.Fields {
+			add(field.Type)
+		}
+	}
+	for _, argsGroup := range service.ArgumentsGroups {
+		for _, arg := range argsGroup.Arguments {
+			add(arg.Type)
+		}
+	}
+	return
+}
+
+func AddTypeImport(file file.File, t *types.Type) {
+	if !t.IsImport {
+		return
+	}
+	if t.Pkg == path.Base(t.PkgImportPath) {
+		file.AddImport("", t.PkgImportPath)
+	} else {
+		file.AddImport(t.Pkg, t.PkgImportPath)
+	}
+}
+
 func GetMethodArguments(args []*types.Argument) []string {
 	var a []string
 	for _, arg := range args {
@@ -161,7 +219,7 @@ func IsLoggingEnabled(service types.Service) bool {
 
 func IsServerEnabled(service types.Service) bool {
 	for _, method := range service.Methods {
-		if method.Generate.HasAny(constants.MethodGenerateHTTPServerFlag, constants.MethodGenerateGRPCServerFlag) {
+		if method.Generate.HasAny(constants.MethodGenerateHTTPServerFlag, constants.MethodGenerateGRPCServerFlag, constants.MethodGenerateJSONRPCServerFlag) {
 			return true
 		}
 	}
@@ -303,6 +361,60 @@ func IsGRPCClientEnabled(service types.Service) bool {
 	return false
 }
 
+func IsJSONRPCEnabled(service types.Service) bool {
+	for _, method := range service.Methods {
+		if method.Generate.HasAny(constants.MethodGenerateJSONRPCServerFlag, constants.MethodGenerateJSONRPCClientFlag) {
+			return true
+		}
+	}
+	return false
+}
+
+func IsJSONRPCServerEnabled(service types.Service) bool {
+	for _, method := range service.Methods {
+		if method.Generate.Has(constants.MethodGenerateJSONRPCServerFlag) {
+			return true
+		}
+	}
+	return false
+}
+
+func IsJSONRPCClientEnabled(service types.Service) bool {
+	for _, method := range service.Methods {
+		if method.Generate.Has(constants.MethodGenerateJSONRPCClientFlag) {
+			return true
+		}
+	}
+	return false
+}
+
+func IsMQEnabled(service types.Service) bool {
+	for _, method := range service.Methods {
+		if method.Generate.HasAny(constants.MethodGenerateMQServerFlag, constants.MethodGenerateMQClientFlag) {
+			return true
+		}
+	}
+	return false
+}
+
+func IsMQServerEnabled(service types.Service) bool {
+	for _, method := range service.Methods {
+		if method.Generate.Has(constants.MethodGenerateMQServerFlag) {
+			return true
+		}
+	}
+	return false
+}
+
+func IsMQClientEnabled(service types.Service) bool {
+	for _, method := range service.Methods {
+		if method.Generate.Has(constants.MethodGenerateMQClientFlag) {
+			return true
+		}
+	}
+	return false
+}
+
 func IsTracingEnabled(service types.Service) bool {
 	for _, method := range service.Methods {
 		if method.Generate.Has(constants.MethodGenerateTracingFlag) {
@@ -385,6 +497,12 @@ func GetMethodsWithCachingEnabled(service types.Service) (ms []types.Method) {
 	})
 }
 
+func GetMethodsPublishingEvents(service types.Service) (ms []types.Method) {
+	return FilteredMethods(service.Methods, func(method types.Method) bool {
+		return method.Generate.Has(constants.MethodGenerateMiddlewareFlag) && len(method.Options.Events.Publishes) > 0
+	})
+}
+
 func GetMethodsWithLoggingEnabled(service types.Service) (ms []types.Method) {
 	return FilteredMethods(service.Methods, func(method types.Method) bool {
 		return method.Generate.Has(constants.MethodGenerateLoggingFlag)
@@ -423,7 +541,7 @@ func GetMethodsWithHTTPServerEnabled(service types.Service) (ms []types.Method)
 
 func GetMethodsWithHTTPClientEnabled(service types.Service) (ms []types.Method) {
 	return FilteredMethods(service.Methods, func(method types.Method) bool {
-		return method.Generate.Has(constants.MethodGenerateHTTPServerFlag)
+		return method.Generate.Has(constants.MethodGenerateHTTPClientFlag)
 	})
 }
 
@@ -451,6 +569,60 @@ func GetMethodsWithGRPCEnabled(service types.Service) (ms []types.Method) {
 	})
 }
 
+func GetUnaryMethodsWithGRPCEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(GetMethodsWithGRPCEnabled(service), func(method types.Method) bool {
+		return !method.IsStreaming()
+	})
+}
+
+func GetStreamingMethodsWithGRPCServerEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(GetMethodsWithGRPCServerEnabled(service), func(method types.Method) bool {
+		return method.IsStreaming()
+	})
+}
+
+func GetStreamingMethodsWithGRPCClientEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(GetMethodsWithGRPCClientEnabled(service), func(method types.Method) bool {
+		return method.IsStreaming()
+	})
+}
+
+func GetMethodsWithJSONRPCServerEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(service.Methods, func(method types.Method) bool {
+		return method.Generate.Has(constants.MethodGenerateJSONRPCServerFlag)
+	})
+}
+
+func GetMethodsWithJSONRPCClientEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(service.Methods, func(method types.Method) bool {
+		return method.Generate.Has(constants.MethodGenerateJSONRPCClientFlag)
+	})
+}
+
+func GetMethodsWithJSONRPCEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(service.Methods, func(method types.Method) bool {
+		return method.Generate.HasAny(constants.MethodGenerateJSONRPCServerFlag, constants.MethodGenerateJSONRPCClientFlag)
+	})
+}
+
+func GetMethodsWithMQServerEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(service.Methods, func(method types.Method) bool {
+		return method.Generate.Has(constants.MethodGenerateMQServerFlag)
+	})
+}
+
+func GetMethodsWithMQClientEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(service.Methods, func(method types.Method) bool {
+		return method.Generate.Has(constants.MethodGenerateMQClientFlag)
+	})
+}
+
+func GetMethodsWithMQEnabled(service types.Service) (ms []types.Method) {
+	return FilteredMethods(service.Methods, func(method types.Method) bool {
+		return method.Generate.HasAny(constants.MethodGenerateMQServerFlag, constants.MethodGenerateMQClientFlag)
+	})
+}
+
 func GetMethodsWithTracingEnabled(service types.Service) (ms []types.Method) {
 	return FilteredMethods(service.Methods, func(method types.Method) bool {
 		return method.Generate.Has(constants.MethodGenerateTracingFlag)
@@ -515,6 +687,10 @@ func IsValidatable(service types.Service) bool {
 	return false
 }
 
+func IsPublishing(service types.Service) bool {
+	return len(GetMethodsPublishingEvents(service)) > 0
+}
+
 func containsNamesAliases(ss []string, name string, alias string) bool {
 	for i := range ss {
 		if strings.ToLower(ss[i]) == strings.ToLower(name) || (len(strs.TrimSpace(alias)) > 0 && strings.ToLower(ss[i]) == strings.ToLower(alias)) {
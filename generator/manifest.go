@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wlMalk/goms/version"
+)
+
+const ManifestFileName = ".goms-manifest.json"
+
+type Manifest struct {
+	base    string
+	Version string         `json:"version"`
+	Files   []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Path      string `json:"path"`
+	Spec      string `json:"spec,omitempty"`
+	Overwrite bool   `json:"overwrite"`
+	Hash      string `json:"hash"`
+}
+
+func LoadManifest(base string) (*Manifest, error) {
+	m := &Manifest{base: base}
+	b, err := ioutil.ReadFile(filepath.Join(base, ManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewManifest records the changes of a run of the given specs, keeping the
+// files of the previous manifest generated by the specs which did not run.
+func NewManifest(base string, changes FileChanges, previous *Manifest, specs []string) *Manifest {
+	m := &Manifest{base: base, Version: version.VERSION}
+	generated := map[string]bool{}
+	for _, change := range changes {
+		rel := manifestPath(base, change.Path)
+		generated[rel] = true
+		if change.Action == FileDelete {
+			continue
+		}
+		f := ManifestFile{Path: rel, Spec: change.Spec, Overwrite: change.Overwrite}
+		switch change.Action {
+		case FileSkip, FileOrphan:
+			if prev := previous.Get(rel); prev != nil {
+				f = *prev
+				f.Spec = change.Spec
+			} else {
+				f.Hash = hash(change.Existing)
+			}
+		case FileUnchanged:
+			f.Hash = hash(change.Existing)
+		default:
+			f.Hash = hash(change.Content)
+		}
+		m.Files = append(m.Files, f)
+	}
+	if previous != nil {
+		ran := specsSet(specs)
+		for _, f := range previous.Files {
+			if !generated[f.Path] && !ran[f.Spec] {
+				m.Files = append(m.Files, f)
+			}
+		}
+	}
+	sort.Slice(m.Files, func(i, j int) bool {
+		return m.Files[i].Path < m.Files[j].Path
+	})
+	return m
+}
+
+func (m *Manifest) Get(path string) *ManifestFile {
+	if m == nil {
+		return nil
+	}
+	for i := range m.Files {
+		if m.Files[i].Path == path {
+			return &m.Files[i]
+		}
+	}
+	return nil
+}
+
+func (m *Manifest) Save() error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(m.base, 0700)
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(m.base, ManifestFileName), append(b, '\n'))
+}
+
+func (m *Manifest) Edited(changes FileChanges) (paths []string) {
+	for _, change := range changes {
+		if change.Action != FileOverwrite || change.Existing == nil {
+			continue
+		}
+		prev := m.Get(manifestPath(m.base, change.Path))
+		if prev != nil && prev.Overwrite && prev.Hash != hash(change.Existing) {
+			paths = append(paths, change.Path)
+		}
+	}
+	return
+}
+
+// Orphans returns the files of the given specs which are not generated
+// anymore. Only the unedited files which are overwritten on every run are
+// deleted, the others are kept as orphans.
+func (m *Manifest) Orphans(changes FileChanges, specs []string) (orphans FileChanges, err error) {
+	generated := map[string]bool{}
+	for _, change := range changes {
+		generated[manifestPath(m.base, change.Path)] = true
+	}
+	ran := specsSet(specs)
+	for _, f := range m.Files {
+		if generated[f.Path] || !ran[f.Spec] {
+			continue
+		}
+		change := FileChange{
+			Path:      filepath.Join(m.base, filepath.FromSlash(f.Path)),
+			Spec:      f.Spec,
+			Overwrite: f.Overwrite,
+		}
+		change.Existing, err = ioutil.ReadFile(change.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = nil
+				continue
+			}
+			return nil, err
+		}
+		if f.Overwrite && hash(change.Existing) == f.Hash {
+			change.Action = FileDelete
+		} else {
+			change.Action = FileOrphan
+		}
+		orphans = append(orphans, change)
+	}
+	return
+}
+
+func specsSet(specs []string) map[string]bool {
+	set := map[string]bool{}
+	for _, spec := range specs {
+		set[spec] = true
+	}
+	return set
+}
+
+func manifestPath(base string, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func hash(b []byte) string {
+	sum := sha256.Sum256(withoutGenerationTime(b))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func testSpecs(enabled map[string]bool) []GeneratorOption {
+	var opts []GeneratorOption
+	for _, name := range []string{"a", "b", "c", "d"} {
+		name := name
+		opts = append(opts, func(g *Generator) {
+			g.AddSpec(name, file.NewSpec("go").
+				Path("", nil).
+				Name(name, nil).
+				Overwrite(name != "d", nil).
+				Conditions(func(service types.Service) bool {
+					return enabled[name]
+				}))
+			g.AddServiceGenerator(name, name, func(file file.File, service types.Service) error {
+				file.P("var _ = 0")
+				return nil
+			})
+		})
+	}
+	return append(opts, builtInFileCreators...)
+}
+
+func testRun(t *testing.T, base string, opts ...GeneratorOption) FileChanges {
+	g := New(opts...)
+	fs, err := g.Generate(types.Service{Path: base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := fs.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := LoadManifest(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orphans, err := manifest.Orphans(changes, g.GeneratedSpecs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes = append(changes, orphans...)
+	if err := changes.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewManifest(base, changes, manifest, g.GeneratedSpecs()).Save(); err != nil {
+		t.Fatal(err)
+	}
+	return changes
+}
+
+func testExists(t *testing.T, base string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(base, name+".go")); err != nil {
+			t.Errorf("expected '%s.go' to exist: %v", name, err)
+		}
+	}
+}
+
+func testActions(changes FileChanges) map[string]FileAction {
+	actions := map[string]FileAction{}
+	for _, change := range changes {
+		actions[filepath.Base(change.Path)] = change.Action
+	}
+	return actions
+}
+
+func TestManifestFilteredRun(t *testing.T) {
+	base, err := ioutil.TempDir("", "goms-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	enabled := map[string]bool{"a": true, "b": true, "c": true, "d": true}
+	testRun(t, base, testSpecs(enabled)...)
+	testExists(t, base, "a", "b", "c", "d")
+
+	// a run of a only keeps the files of the other specs and their entries
+	changes := testRun(t, base, append(testSpecs(enabled), IncludeSpecs("a"))...)
+	if len(changes.Stale()) != 0 {
+		t.Fatalf("expected no stale files, got %v", testActions(changes.Stale()))
+	}
+	testExists(t, base, "a", "b", "c", "d")
+	manifest, err := LoadManifest(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go"} {
+		if f := manifest.Get(name); f == nil || f.Spec != name[:1] {
+			t.Errorf("expected '%s' in the manifest with its spec, got %v", name, f)
+		}
+	}
+
+	// excluded specs are not orphaned either
+	changes = testRun(t, base, append(testSpecs(enabled), ExcludeSpecs("b", "c", "d"))...)
+	if len(changes.Stale()) != 0 {
+		t.Fatalf("expected no stale files, got %v", testActions(changes.Stale()))
+	}
+	testExists(t, base, "a", "b", "c", "d")
+
+	// specs which ran without generating their files orphan them
+	if err := ioutil.WriteFile(filepath.Join(base, "c.go"), []byte("package p\n\n// edited\nvar _ = 0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	enabled = map[string]bool{"a": true}
+	actions := testActions(testRun(t, base, testSpecs(enabled)...))
+	if actions["b.go"] != FileDelete {
+		t.Errorf("expected unedited 'b.go' to be deleted, got '%s'", actions["b.go"])
+	}
+	if actions["c.go"] != FileOrphan {
+		t.Errorf("expected edited 'c.go' to be kept, got '%s'", actions["c.go"])
+	}
+	if actions["d.go"] != FileOrphan {
+		t.Errorf("expected user-editable 'd.go' to be kept, got '%s'", actions["d.go"])
+	}
+	if _, err := os.Stat(filepath.Join(base, "b.go")); !os.IsNotExist(err) {
+		t.Errorf("expected 'b.go' to be deleted")
+	}
+	testExists(t, base, "a", "c", "d")
+}
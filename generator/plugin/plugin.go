@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	strs "strings"
+
+	"github.com/wlMalk/goms/parser/types"
+	"github.com/wlMalk/goms/version"
+)
+
+const ExecutablePrefix = "goms-gen-"
+
+type Request struct {
+	GomsVersion string        `json:"gomsVersion"`
+	Parameter   string        `json:"parameter,omitempty"`
+	Service     types.Service `json:"service"`
+}
+
+type Response struct {
+	Error string `json:"error,omitempty"`
+	Files []File `json:"files,omitempty"`
+}
+
+type File struct {
+	Path      string `json:"path"`
+	Content   string `json:"content"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+	Merge     bool   `json:"merge,omitempty"`
+}
+
+type Plugin struct {
+	Name      string
+	Command   string
+	Parameter string
+}
+
+func New(name string, parameter string) *Plugin {
+	command := name
+	if !strs.ContainsRune(name, filepath.Separator) && !strs.ContainsRune(name, '/') {
+		command = ExecutablePrefix + name
+	} else {
+		name = strs.TrimPrefix(filepath.Base(name), ExecutablePrefix)
+	}
+	return &Plugin{Name: name, Command: command, Parameter: parameter}
+}
+
+func (p *Plugin) Run(service types.Service) ([]File, error) {
+	command, err := exec.LookPath(p.Command)
+	if err != nil {
+		return nil, fmt.Errorf("plugin '%s' was not found: %v", p.Name, err)
+	}
+	req, err := json.Marshal(Request{
+		GomsVersion: version.VERSION,
+		Parameter:   p.Parameter,
+		Service:     service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stdout := new(bytes.Buffer)
+	cmd := exec.Command(command)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("plugin '%s' failed: %v", p.Name, err)
+	}
+	res := &Response{}
+	if err := json.Unmarshal(stdout.Bytes(), res); err != nil {
+		return nil, fmt.Errorf("plugin '%s' returned an invalid response: %v", p.Name, err)
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("plugin '%s' failed: %s", p.Name, res.Error)
+	}
+	for _, f := range res.Files {
+		if err := validatePath(f.Path); err != nil {
+			return nil, fmt.Errorf("plugin '%s' returned an invalid file: %v", p.Name, err)
+		}
+	}
+	return res.Files, nil
+}
+
+func validatePath(p string) error {
+	if p == "" {
+		return fmt.Errorf("empty path")
+	}
+	clean := filepath.Clean(filepath.FromSlash(p))
+	if filepath.IsAbs(clean) || clean == ".." || strs.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path '%s' is outside of the service directory", p)
+	}
+	return nil
+}
+
+// Main is the entry point of plugins written in Go: it reads the request from
+// stdin, calls generate and writes its files or error as the response to stdout.
+func Main(generate func(req *Request) ([]File, error)) {
+	res := &Response{}
+	req, err := ReadRequest(os.Stdin)
+	if err == nil {
+		res.Files, err = generate(req)
+	}
+	if err != nil {
+		res = &Response{Error: err.Error()}
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(res); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func ReadRequest(r io.Reader) (*Request, error) {
+	req := &Request{}
+	if err := json.NewDecoder(r).Decode(req); err != nil {
+		return nil, fmt.Errorf("cannot read plugin request: %v", err)
+	}
+	return req, nil
+}
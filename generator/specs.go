@@ -50,10 +50,29 @@ func ProtoBufServiceDefinitionsFileSpec(g *Generator) {
 	g.AddEnumGenerator(constants.SpecNameProtoBufServiceDefinitions, constants.EnumGeneratorProtoBufEnumDefinition, generators.ProtoBufEnumDefinition)
 }
 
+func OpenAPIFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameOpenAPI,
+		file.NewSpec("yaml").
+			Name("openapi", nil).
+			Overwrite(true, nil).
+			Conditions(func(service types.Service) bool {
+				return service.Generate.Has(constants.ServiceGenerateOpenAPIFlag) && helpers.IsHTTPServerEnabled(service)
+			}).
+			Before(file.SpecBeforeFunc(func(file file.File, service types.Service) {
+				file.(*files.TextFile).CommentFormat("# %s")
+			})))
+	g.AddServiceGenerator(constants.SpecNameOpenAPI, constants.ServiceGeneratorOpenAPIDocument, generators.OpenAPIDocument)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameOpenAPI, constants.MethodGeneratorOpenAPIMethodSchemas, generators.OpenAPIMethodSchemas, helpers.GetMethodsWithHTTPServerEnabled)
+	g.AddEntityGenerator(constants.SpecNameOpenAPI, constants.EntityGeneratorOpenAPIEntitySchema, generators.OpenAPIEntitySchema)
+	g.AddArgumentsGroupGenerator(constants.SpecNameOpenAPI, constants.ArgumentsGroupGeneratorOpenAPIArgumentsGroupSchema, generators.OpenAPIArgumentsGroupSchema)
+	g.AddEnumGenerator(constants.SpecNameOpenAPI, constants.EnumGeneratorOpenAPIEnumSchema, generators.OpenAPIEnumSchema)
+}
+
 func ServiceMainFileSpec(g *Generator) {
 	g.AddSpec(constants.SpecNameServiceMain,
 		file.NewSpec("go").
 			Name("main", nil).
+			Merge(true, nil).
 			Conditions(func(service types.Service) bool {
 				return service.Generate.Has(constants.ServiceGenerateMainFlag)
 			}).
@@ -85,6 +104,7 @@ func ServiceStartCMDFileSpec(g *Generator) {
 	g.AddServiceGeneratorWithConditions(constants.SpecNameServiceStartCMD, constants.ServiceGeneratorServiceMainInterruptHandlerFunc, generators.ServiceMainInterruptHandlerFunc, helpers.IsServerEnabled)
 	g.AddServiceGeneratorWithConditions(constants.SpecNameServiceStartCMD, constants.ServiceGeneratorServiceMainServeGRPCFunc, generators.ServiceMainServeGRPCFunc, helpers.IsGRPCServerEnabled)
 	g.AddServiceGeneratorWithConditions(constants.SpecNameServiceStartCMD, constants.ServiceGeneratorServiceMainServeHTTPFunc, generators.ServiceMainServeHTTPFunc, helpers.IsHTTPServerEnabled)
+	g.AddServiceGeneratorWithConditions(constants.SpecNameServiceStartCMD, constants.ServiceGeneratorServiceMainServeJSONRPCFunc, generators.ServiceMainServeJSONRPCFunc, helpers.IsJSONRPCServerEnabled)
 }
 
 func CachingMiddlewareFileSpec(g *Generator) {
@@ -100,6 +120,18 @@ func CachingMiddlewareFileSpec(g *Generator) {
 	g.AddMethodGenerator(constants.SpecNameCachingMiddleware, constants.MethodGeneratorCachingMiddlewareMethodFunc, generators.CachingMiddlewareMethodFunc)
 }
 
+func PublishingMiddlewareFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNamePublishingMiddleware,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "service", "middleware"), nil).
+			Name("publishing_middleware.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsMiddlewareEnabled, helpers.IsPublishing))
+	g.AddServiceGenerator(constants.SpecNamePublishingMiddleware, constants.ServiceGeneratorPublishingMiddlewareStruct, generators.PublishingMiddlewareStruct)
+	g.AddServiceGenerator(constants.SpecNamePublishingMiddleware, constants.ServiceGeneratorPublishingMiddlewareNewFunc, generators.PublishingMiddlewareNewFunc)
+	g.AddMethodGenerator(constants.SpecNamePublishingMiddleware, constants.MethodGeneratorPublishingMiddlewareMethodFunc, generators.PublishingMiddlewareMethodFunc)
+}
+
 func ConvertersFileSpec(g *Generator) {
 	g.AddSpec(constants.SpecNameConverters,
 		file.NewSpec("go").
@@ -133,6 +165,7 @@ func ServiceImplementationFileSpec(g *Generator) {
 			Name("", func(service types.Service) string {
 				return strings.ToLower(strings.ToSnakeCase(service.Name))
 			}).
+			Merge(true, nil).
 			Conditions(helpers.IsMethodStubsEnabled))
 	g.AddServiceGenerator(constants.SpecNameServiceImplementation, constants.ServiceGeneratorServiceImplementationStruct, generators.ServiceImplementationStruct)
 	g.AddServiceGenerator(constants.SpecNameServiceImplementation, constants.ServiceGeneratorServiceImplementationStructNewFunc, generators.ServiceImplementationStructNewFunc)
@@ -146,6 +179,7 @@ func ServiceImplementationValidatorFileSpec(g *Generator) {
 				return strings.ToLower(strings.ToSnakeCase(service.Name))
 			}).
 			Name("validator", nil).
+			Merge(true, nil).
 			Conditions(helpers.IsMiddlewareEnabled, helpers.IsValidatingEnabled, helpers.IsValidatable))
 	g.AddServiceGenerator(constants.SpecNameServiceImplementationValidator, constants.MethodGeneratorServiceMethodImplementationValidatorStruct, generators.ServiceMethodImplementationValidatorStruct)
 	g.AddMethodGeneratorWithExtractor(constants.SpecNameServiceImplementationValidator, constants.MethodGeneratorServiceMethodImplementationValidateFunc, generators.ServiceMethodImplementationValidateFunc, helpers.GetMethodsWithValidatingEnabled)
@@ -158,6 +192,7 @@ func ServiceImplementationMiddlewareFileSpec(g *Generator) {
 				return strings.ToLower(strings.ToSnakeCase(service.Name))
 			}).
 			Name("middleware", nil).
+			Merge(true, nil).
 			Conditions(helpers.IsMiddlewareEnabled))
 	g.AddMethodGeneratorWithExtractor(constants.SpecNameServiceImplementationMiddleware, constants.MethodGeneratorServiceMethodImplementationMiddleware, generators.ServiceMethodImplementationMiddleware, helpers.GetMethodsWithMiddlewareEnabled)
 	g.AddMethodGeneratorWithExtractor(constants.SpecNameServiceImplementationMiddleware, constants.MethodGeneratorServiceMethodImplementationOuterMiddleware, generators.ServiceMethodImplementationOuterMiddleware, helpers.GetMethodsWithMiddlewareEnabled)
@@ -173,6 +208,7 @@ func CachingKeyerFileSpec(g *Generator) {
 				return strings.ToLower(strings.ToSnakeCase(service.Name))
 			}).
 			Name("caching_keyer", nil).
+			Merge(true, nil).
 			Conditions(helpers.IsMiddlewareEnabled, helpers.IsCachingEnabled, helpers.IsCachaeble))
 	g.AddServiceGenerator(constants.SpecNameCachingKeyer, constants.ServiceGeneratorCachingMiddlewareCacheKeyerType, generators.CachingMiddlewareCacheKeyerType)
 	g.AddServiceGenerator(constants.SpecNameCachingKeyer, constants.ServiceGeneratorCachingMiddlewareKeyerNewFunc, generators.CachingMiddlewareKeyerNewFunc)
@@ -250,6 +286,7 @@ func ValidatingMiddlewareFileSpec(g *Generator) {
 			Name("validating_middleware.goms", nil).
 			Overwrite(true, nil).
 			Conditions(helpers.IsMiddlewareEnabled, helpers.IsValidatingEnabled, helpers.IsValidatable))
+	g.AddServiceGenerator(constants.SpecNameValidatingMiddleware, constants.ServiceGeneratorValidatingRegexps, generators.ValidatingRegexps)
 	g.AddServiceGenerator(constants.SpecNameValidatingMiddleware, constants.ServiceGeneratorValidatingValidatorsTypes, generators.ValidatingValidatorsTypes)
 	g.AddServiceGenerator(constants.SpecNameValidatingMiddleware, constants.ServiceGeneratorValidatingMiddlewareStruct, generators.ValidatingMiddlewareStruct)
 	g.AddServiceGenerator(constants.SpecNameValidatingMiddleware, constants.ServiceGeneratorValidatingMiddlewareNewFunc, generators.ValidatingMiddlewareNewFunc)
@@ -267,6 +304,7 @@ func GRPCClientFileSpec(g *Generator) {
 	g.AddServiceGenerator(constants.SpecNameGRPCClient, constants.ServiceGeneratorGRPCTransportClientNewFunc, generators.GRPCTransportClientNewFunc)
 	g.AddServiceGenerator(constants.SpecNameGRPCClient, constants.ServiceGeneratorGRPCTransportClientNewSpecialFunc, generators.GRPCTransportClientNewSpecialFunc)
 	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCClient, constants.MethodGeneratorGRPCTransportClientMethodFunc, generators.GRPCTransportClientMethodFunc, helpers.GetMethodsWithGRPCClientEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCClient, constants.MethodGeneratorGRPCTransportClientStreamEndpointFunc, generators.GRPCTransportClientStreamEndpointFunc, helpers.GetStreamingMethodsWithGRPCClientEnabled)
 }
 
 func GlobalGRPCClientFileSpec(g *Generator) {
@@ -289,8 +327,8 @@ func GRPCDecodersFileSpec(g *Generator) {
 			Name("decoders.goms", nil).
 			Overwrite(true, nil).
 			Conditions(helpers.IsGRPCEnabled))
-	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCDecoders, constants.MethodGeneratorGRPCRequestDecoder, generators.GRPCRequestDecoder, helpers.GetMethodsWithGRPCEnabled)
-	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCDecoders, constants.MethodGeneratorGRPCResponseDecoder, generators.GRPCResponseDecoder, helpers.GetMethodsWithGRPCEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCDecoders, constants.MethodGeneratorGRPCRequestDecoder, generators.GRPCRequestDecoder, helpers.GetUnaryMethodsWithGRPCEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCDecoders, constants.MethodGeneratorGRPCResponseDecoder, generators.GRPCResponseDecoder, helpers.GetUnaryMethodsWithGRPCEnabled)
 }
 
 func GRPCEncodersFileSpec(g *Generator) {
@@ -300,8 +338,8 @@ func GRPCEncodersFileSpec(g *Generator) {
 			Name("encoders.goms", nil).
 			Overwrite(true, nil).
 			Conditions(helpers.IsGRPCEnabled))
-	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCEncoders, constants.MethodGeneratorGRPCRequestEncoder, generators.GRPCRequestEncoder, helpers.GetMethodsWithGRPCEnabled)
-	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCEncoders, constants.MethodGeneratorGRPCResponseEncoder, generators.GRPCResponseEncoder, helpers.GetMethodsWithGRPCEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCEncoders, constants.MethodGeneratorGRPCRequestEncoder, generators.GRPCRequestEncoder, helpers.GetUnaryMethodsWithGRPCEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameGRPCEncoders, constants.MethodGeneratorGRPCResponseEncoder, generators.GRPCResponseEncoder, helpers.GetUnaryMethodsWithGRPCEnabled)
 }
 
 func GRPCServerFileSpec(g *Generator) {
@@ -403,6 +441,161 @@ func HTTPServerFileSpec(g *Generator) {
 	g.AddServiceGenerator(constants.SpecNameHTTPServer, constants.ServiceGeneratorHTTPTransportServerRegisterSpecialFunc, generators.HTTPTransportServerRegisterSpecialFunc)
 }
 
+func JSONRPCClientFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameJSONRPCClient,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "jsonrpc", "client"), nil).
+			Name("client.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsJSONRPCClientEnabled))
+	g.AddServiceGenerator(constants.SpecNameJSONRPCClient, constants.ServiceGeneratorJSONRPCTransportClientStruct, generators.JSONRPCTransportClientStruct)
+	g.AddServiceGenerator(constants.SpecNameJSONRPCClient, constants.ServiceGeneratorJSONRPCTransportClientNewFunc, generators.JSONRPCTransportClientNewFunc)
+	g.AddServiceGenerator(constants.SpecNameJSONRPCClient, constants.ServiceGeneratorJSONRPCTransportClientNewSpecialFunc, generators.JSONRPCTransportClientNewSpecialFunc)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameJSONRPCClient, constants.MethodGeneratorJSONRPCTransportClientMethodFunc, generators.JSONRPCTransportClientMethodFunc, helpers.GetMethodsWithJSONRPCClientEnabled)
+}
+
+func GlobalJSONRPCClientFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameGlobalJSONRPCClient,
+		file.NewSpec("go").
+			Path("", func(service types.Service) string {
+				return filepath.Join("clients", "jsonrpc", strings.ToLower(strings.ToSnakeCase(service.Name)))
+			}).
+			Name("client.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsJSONRPCClientEnabled))
+	g.AddServiceGenerator(constants.SpecNameGlobalJSONRPCClient, constants.ServiceGeneratorJSONRPCTransportClientGlobalVar, generators.JSONRPCTransportClientGlobalVar)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameGlobalJSONRPCClient, constants.MethodGeneratorJSONRPCTransportClientGlobalFunc, generators.JSONRPCTransportClientGlobalFunc, helpers.GetMethodsWithJSONRPCClientEnabled)
+}
+
+func JSONRPCDecodersFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameJSONRPCDecoders,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "jsonrpc"), nil).
+			Name("decoders.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsJSONRPCEnabled))
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameJSONRPCDecoders, constants.MethodGeneratorJSONRPCRequestDecoder, generators.JSONRPCRequestDecoder, helpers.GetMethodsWithJSONRPCEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameJSONRPCDecoders, constants.MethodGeneratorJSONRPCResponseDecoder, generators.JSONRPCResponseDecoder, helpers.GetMethodsWithJSONRPCEnabled)
+}
+
+func JSONRPCEncodersFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameJSONRPCEncoders,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "jsonrpc"), nil).
+			Name("encoders.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsJSONRPCEnabled))
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameJSONRPCEncoders, constants.MethodGeneratorJSONRPCRequestEncoder, generators.JSONRPCRequestEncoder, helpers.GetMethodsWithJSONRPCEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameJSONRPCEncoders, constants.MethodGeneratorJSONRPCResponseEncoder, generators.JSONRPCResponseEncoder, helpers.GetMethodsWithJSONRPCEnabled)
+}
+
+func JSONRPCMessagesFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameJSONRPCMessages,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "jsonrpc"), nil).
+			Name("messages.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsJSONRPCEnabled))
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameJSONRPCMessages, constants.MethodGeneratorJSONRPCParams, generators.JSONRPCParams, helpers.GetMethodsWithJSONRPCEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameJSONRPCMessages, constants.MethodGeneratorJSONRPCResult, generators.JSONRPCResult, helpers.GetMethodsWithJSONRPCEnabled)
+}
+
+func JSONRPCServerFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameJSONRPCServer,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "jsonrpc", "server"), nil).
+			Name("server.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsJSONRPCServerEnabled))
+	g.AddServiceGenerator(constants.SpecNameJSONRPCServer, constants.ServiceGeneratorJSONRPCTransportServerRegisterFunc, generators.JSONRPCTransportServerRegisterFunc)
+	g.AddServiceGenerator(constants.SpecNameJSONRPCServer, constants.ServiceGeneratorJSONRPCTransportServerRegisterSpecialFunc, generators.JSONRPCTransportServerRegisterSpecialFunc)
+}
+
+func MQClientFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameMQClient,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "mq", "client"), nil).
+			Name("client.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsMQClientEnabled))
+	g.AddServiceGenerator(constants.SpecNameMQClient, constants.ServiceGeneratorMQTransportClientStruct, generators.MQTransportClientStruct)
+	g.AddServiceGenerator(constants.SpecNameMQClient, constants.ServiceGeneratorMQTransportClientNewFunc, generators.MQTransportClientNewFunc)
+	g.AddServiceGenerator(constants.SpecNameMQClient, constants.ServiceGeneratorMQTransportClientNewSpecialFunc, generators.MQTransportClientNewSpecialFunc)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameMQClient, constants.MethodGeneratorMQTransportClientMethodFunc, generators.MQTransportClientMethodFunc, helpers.GetMethodsWithMQClientEnabled)
+}
+
+func GlobalMQClientFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameGlobalMQClient,
+		file.NewSpec("go").
+			Path("", func(service types.Service) string {
+				return filepath.Join("clients", "mq", strings.ToLower(strings.ToSnakeCase(service.Name)))
+			}).
+			Name("client.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsMQClientEnabled))
+	g.AddServiceGenerator(constants.SpecNameGlobalMQClient, constants.ServiceGeneratorMQTransportClientGlobalVar, generators.MQTransportClientGlobalVar)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameGlobalMQClient, constants.MethodGeneratorMQTransportClientGlobalFunc, generators.MQTransportClientGlobalFunc, helpers.GetMethodsWithMQClientEnabled)
+}
+
+func MQDecodersFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameMQDecoders,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "mq"), nil).
+			Name("decoders.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsMQEnabled))
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameMQDecoders, constants.MethodGeneratorMQRequestDecoder, generators.MQRequestDecoder, helpers.GetMethodsWithMQEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameMQDecoders, constants.MethodGeneratorMQResponseDecoder, generators.MQResponseDecoder, helpers.GetMethodsWithMQEnabled)
+}
+
+func MQEncodersFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameMQEncoders,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "mq"), nil).
+			Name("encoders.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsMQEnabled))
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameMQEncoders, constants.MethodGeneratorMQRequestEncoder, generators.MQRequestEncoder, helpers.GetMethodsWithMQEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameMQEncoders, constants.MethodGeneratorMQResponseEncoder, generators.MQResponseEncoder, helpers.GetMethodsWithMQEnabled)
+}
+
+func MQMessagesFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameMQMessages,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "mq"), nil).
+			Name("messages.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsMQEnabled))
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameMQMessages, constants.MethodGeneratorMQRequest, generators.MQRequest, helpers.GetMethodsWithMQEnabled)
+	g.AddMethodGeneratorWithExtractor(constants.SpecNameMQMessages, constants.MethodGeneratorMQResponse, generators.MQResponse, helpers.GetMethodsWithMQEnabled)
+}
+
+func MQServerFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameMQServer,
+		file.NewSpec("go").
+			Path(filepath.Join("pkg", "transport", "mq", "server"), nil).
+			Name("server.goms", nil).
+			Overwrite(true, nil).
+			Conditions(helpers.IsMQServerEnabled))
+	g.AddServiceGenerator(constants.SpecNameMQServer, constants.ServiceGeneratorMQTransportServerRegisterFunc, generators.MQTransportServerRegisterFunc)
+	g.AddServiceGenerator(constants.SpecNameMQServer, constants.ServiceGeneratorMQTransportServerRegisterSpecialFunc, generators.MQTransportServerRegisterSpecialFunc)
+}
+
+func ProtoEntitiesConvertersFileSpec(g *Generator) {
+	g.AddSpec(constants.SpecNameProtoEntitiesConverters,
+		file.NewSpec("go").
+			Path("", func(service types.Service) string {
+				return filepath.Join("pkg", "protobuf", strings.ToLower(strings.ToSnakeCase(service.Name)), "entities")
+			}).
+			Name("entities.goms", nil).
+			Overwrite(true, nil).
+			Conditions(func(service types.Service) bool {
+				return service.Generate.Has(constants.ServiceGenerateProtoBufFlag) && helpers.IsGRPCEnabled(service) && len(service.Entities) > 0
+			}))
+	g.AddEntityGenerator(constants.SpecNameProtoEntitiesConverters, constants.EntityGeneratorProtoEntityNewFunc, generators.ProtoEntityNewFunc)
+	g.AddEntityGenerator(constants.SpecNameProtoEntitiesConverters, constants.EntityGeneratorProtoEntityNewProtoFunc, generators.ProtoEntityNewProtoFunc)
+}
+
 func ProtoRequestsConvertersFileSpec(g *Generator) {
 	g.AddSpec(constants.SpecNameProtoRequestsConverters,
 		file.NewSpec("go").
@@ -463,10 +656,8 @@ func ServiceTypesDefinitionsFileSpec(g *Generator) {
 			Path(filepath.Join("pkg", "service", "types"), nil).
 			Name("types.goms", nil).
 			Overwrite(true, nil).
-			Conditions(func(service types.Service) bool {
-				return len(service.Entities) > 0 || len(service.ArgumentsGroups) > 0 || len(service.Enums) > 0
-			}))
-	g.AddEntityGenerator(constants.SpecNameServiceTypesDefinitions, constants.EntityGeneratorServiceEntityType, generators.ServiceEntityType)
+			Conditions(helpers.HasTypesDefinitions))
+	g.AddEntityGeneratorWithConditions(constants.SpecNameServiceTypesDefinitions, constants.EntityGeneratorServiceEntityType, generators.ServiceEntityType, helpers.IsLocalEntity)
 	g.AddArgumentsGroupGenerator(constants.SpecNameServiceTypesDefinitions, constants.ArgumentsGroupGeneratorServiceArgumentsGroupType, generators.ServiceArgumentsGroupType)
 	g.AddEnumGenerator(constants.SpecNameServiceTypesDefinitions, constants.EnumGeneratorServiceEnumType, generators.ServiceEnumType)
 }
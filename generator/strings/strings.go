@@ -185,7 +185,7 @@ func CountLeading(s string, seps ...rune) (counter int) {
 }
 
 func Split(s string, sep string, f func(i int, before string, after string) (offset int, to int)) (parts []string) {
-	if len(sep) == 0 || len(sep) >= len(s) {
+	if len(sep) == 0 || len(sep) > len(s) {
 		return nil
 	}
 	p := 0
@@ -199,22 +199,91 @@ func Split(s string, sep string, f func(i int, before string, after string) (off
 			}
 		}
 		if i == len(s)-len(sep) {
-			if offset, to := f(i, s[p:], ""); offset != -1 && to != -1 && p+offset < len(s)-to {
-				parts = append(parts, s[p+offset:len(s)-to])
+			if offset, to := f(i, s[p:], ""); offset != -1 && to != -1 {
+				if p+offset < len(s)-to {
+					parts = append(parts, s[p+offset:len(s)-to])
+				}
+			} else if rest := strings.TrimSpace(s[p:]); len(rest) > 0 {
+				// an unbalanced remainder is kept so that it is reported
+				parts = append(parts, rest)
 			}
 		}
 	}
 	return
 }
 
+// SplitS splits s around sep outside of brackets. The argument of a regex
+// validator is skipped, as regular expressions may hold unbalanced brackets.
 func SplitS(s string, sep string) []string {
 	return Split(s, sep, func(i int, before string, after string) (int, int) {
-		if strings.Count(before, "(") != strings.Count(before, ")") ||
-			strings.Count(before, "[") != strings.Count(before, "]") ||
-			strings.Count(before, "{") != strings.Count(before, "}") ||
-			strings.Count(before, "<") != strings.Count(before, ">") {
+		if !balanced(before) {
 			return -1, -1
 		}
 		return CountLeading(before, ' ', '\t', '\n'), CountLeading(Reverse(before), ' ', '\t', '\n')
 	})
 }
+
+func balanced(s string) bool {
+	counts := map[byte]int{}
+	for i := 0; i < len(s); i++ {
+		if (i == 0 || !isIdentByte(s[i-1])) && strings.HasPrefix(strings.ToLower(s[i:]), "regex(") {
+			end := regexEnd(s, i+len("regex("))
+			if end == -1 {
+				return false
+			}
+			i = end
+			continue
+		}
+		switch s[i] {
+		case '(', '[', '{', '<':
+			counts[s[i]]++
+		case ')':
+			counts['(']--
+		case ']':
+			counts['[']--
+		case '}':
+			counts['{']--
+		case '>':
+			counts['<']--
+		}
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// regexEnd returns the index of the parenthesis closing the regular
+// expression starting at i, or -1 if it is not closed.
+func regexEnd(s string, i int) int {
+	depth, class := 1, false
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case class:
+			class = s[i] != ']'
+		case s[i] == '[':
+			class = true
+			if strings.HasPrefix(s[i+1:], "^]") {
+				i += 2
+			} else if strings.HasPrefix(s[i+1:], "]") {
+				i++
+			}
+		case s[i] == '(':
+			depth++
+		case s[i] == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b == '-' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
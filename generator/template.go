@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	strs "strings"
+	"text/template"
+
+	"github.com/wlMalk/goms/generator/file"
+	"github.com/wlMalk/goms/generator/files"
+	"github.com/wlMalk/goms/generator/helpers"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+const TemplatesDir = ".goms/templates"
+
+type TemplateData struct {
+	Service        types.Service
+	Method         types.Method
+	Entity         types.Entity
+	ArgumentsGroup types.ArgumentsGroup
+	Enum           types.Enum
+}
+
+var templateDirectiveRegexp = regexp.MustCompile(`^\s*\{\{/\*\s*goms:(.*?)\*/\}\}[ \t]*\n?`)
+
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"addImport": func(alias string, path string) string { return "" },
+		"join":      strs.Join,
+		"lower":     strs.ToLower,
+		"upper":     strs.ToUpper,
+
+		"ToUpperFirst": strings.ToUpperFirst,
+		"ToLowerFirst": strings.ToLowerFirst,
+		"ToCamelCase":  strings.ToCamelCase,
+		"ToSnakeCase":  strings.ToSnakeCase,
+		"ToKebabCase":  strings.ToKebabCase,
+
+		"GetName":                            helpers.GetName,
+		"GetMethodArguments":                 helpers.GetMethodArguments,
+		"GetMethodResults":                   helpers.GetMethodResults,
+		"GetMethodArgumentsInCall":           helpers.GetMethodArgumentsInCall,
+		"GetExportedMethodSignature":         helpers.GetExportedMethodSignature,
+		"GetUnexportedMethodSignature":       helpers.GetUnexportedMethodSignature,
+		"GetFieldTagsString":                 helpers.GetFieldTagsString,
+		"GetLoggedArgumentsForMethod":        helpers.GetLoggedArgumentsForMethod,
+		"GetLoggedResultsForMethod":          helpers.GetLoggedResultsForMethod,
+		"HasTypesDefinitions":                helpers.HasTypesDefinitions,
+		"IsLocalEntity":                      helpers.IsLocalEntity,
+		"IsCachingEnabled":                   helpers.IsCachingEnabled,
+		"IsLoggingEnabled":                   helpers.IsLoggingEnabled,
+		"IsServerEnabled":                    helpers.IsServerEnabled,
+		"IsRateLimitingEnabled":              helpers.IsRateLimitingEnabled,
+		"IsCircuitBreakingEnabled":           helpers.IsCircuitBreakingEnabled,
+		"IsMethodStubsEnabled":               helpers.IsMethodStubsEnabled,
+		"IsValidatorsEnabled":                helpers.IsValidatorsEnabled,
+		"IsValidatingEnabled":                helpers.IsValidatingEnabled,
+		"IsMiddlewareEnabled":                helpers.IsMiddlewareEnabled,
+		"IsRecoveringEnabled":                helpers.IsRecoveringEnabled,
+		"IsHTTPEnabled":                      helpers.IsHTTPEnabled,
+		"IsHTTPServerEnabled":                helpers.IsHTTPServerEnabled,
+		"IsHTTPClientEnabled":                helpers.IsHTTPClientEnabled,
+		"IsGRPCEnabled":                      helpers.IsGRPCEnabled,
+		"IsGRPCServerEnabled":                helpers.IsGRPCServerEnabled,
+		"IsGRPCClientEnabled":                helpers.IsGRPCClientEnabled,
+		"IsJSONRPCEnabled":                   helpers.IsJSONRPCEnabled,
+		"IsJSONRPCServerEnabled":             helpers.IsJSONRPCServerEnabled,
+		"IsJSONRPCClientEnabled":             helpers.IsJSONRPCClientEnabled,
+		"IsMQEnabled":                        helpers.IsMQEnabled,
+		"IsMQServerEnabled":                  helpers.IsMQServerEnabled,
+		"IsMQClientEnabled":                  helpers.IsMQClientEnabled,
+		"IsTracingEnabled":                   helpers.IsTracingEnabled,
+		"IsMetricsEnabled":                   helpers.IsMetricsEnabled,
+		"IsValidatable":                      helpers.IsValidatable,
+		"IsCachaeble":                        helpers.IsCachaeble,
+		"IsPublishing":                       helpers.IsPublishing,
+		"GetMethodsWithCachingEnabled":       helpers.GetMethodsWithCachingEnabled,
+		"GetMethodsPublishingEvents":         helpers.GetMethodsPublishingEvents,
+		"GetMethodsWithLoggingEnabled":       helpers.GetMethodsWithLoggingEnabled,
+		"GetMethodsWithMethodStubsEnabled":   helpers.GetMethodsWithMethodStubsEnabled,
+		"GetMethodsWithValidatorsEnabled":    helpers.GetMethodsWithValidatorsEnabled,
+		"GetMethodsWithValidatingEnabled":    helpers.GetMethodsWithValidatingEnabled,
+		"GetMethodsWithMiddlewareEnabled":    helpers.GetMethodsWithMiddlewareEnabled,
+		"GetMethodsWithHTTPServerEnabled":    helpers.GetMethodsWithHTTPServerEnabled,
+		"GetMethodsWithHTTPClientEnabled":    helpers.GetMethodsWithHTTPClientEnabled,
+		"GetMethodsWithHTTPEnabled":          helpers.GetMethodsWithHTTPEnabled,
+		"GetMethodsWithGRPCServerEnabled":    helpers.GetMethodsWithGRPCServerEnabled,
+		"GetMethodsWithGRPCClientEnabled":    helpers.GetMethodsWithGRPCClientEnabled,
+		"GetMethodsWithGRPCEnabled":          helpers.GetMethodsWithGRPCEnabled,
+		"GetMethodsWithJSONRPCServerEnabled": helpers.GetMethodsWithJSONRPCServerEnabled,
+		"GetMethodsWithJSONRPCClientEnabled": helpers.GetMethodsWithJSONRPCClientEnabled,
+		"GetMethodsWithJSONRPCEnabled":       helpers.GetMethodsWithJSONRPCEnabled,
+		"GetMethodsWithMQServerEnabled":      helpers.GetMethodsWithMQServerEnabled,
+		"GetMethodsWithMQClientEnabled":      helpers.GetMethodsWithMQClientEnabled,
+		"GetMethodsWithMQEnabled":            helpers.GetMethodsWithMQEnabled,
+		"GetMethodsWithTracingEnabled":       helpers.GetMethodsWithTracingEnabled,
+		"GetMethodsWithMetricsEnabled":       helpers.GetMethodsWithMetricsEnabled,
+	}
+}
+
+func FindTemplatesDir(dir string, root string) string {
+	dir, root = filepath.Clean(dir), filepath.Clean(root)
+	for {
+		templatesDir := filepath.Join(dir, filepath.FromSlash(TemplatesDir))
+		if info, err := os.Stat(templatesDir); err == nil && info.IsDir() {
+			return templatesDir
+		}
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func TemplateSpecs(dir string) (GeneratorOption, error) {
+	var opts []GeneratorOption
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strs.HasSuffix(info.Name(), ".tmpl") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		opt, err := templateSpec(p, filepath.ToSlash(strs.TrimSuffix(rel, ".tmpl")))
+		if err != nil {
+			return err
+		}
+		opts = append(opts, opt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func(generator *Generator) {
+		for _, opt := range opts {
+			opt(generator)
+		}
+	}, nil
+}
+
+func templateSpec(filePath string, output string) (GeneratorOption, error) {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	src := string(b)
+	name := "template:" + output
+	overwrite, merge := true, false
+	var flags []string
+	if m := templateDirectiveRegexp.FindStringSubmatch(src); m != nil {
+		src = src[len(m[0]):]
+		for _, directive := range strs.Fields(m[1]) {
+			kv := strs.SplitN(directive, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid directive '%s' in template '%s'", directive, filePath)
+			}
+			switch kv[0] {
+			case "name":
+				name = kv[1]
+			case "overwrite", "merge":
+				v, err := strconv.ParseBool(kv[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid value '%s' for '%s' directive in template '%s'", kv[1], kv[0], filePath)
+				}
+				if kv[0] == "overwrite" {
+					overwrite = v
+				} else {
+					merge = v
+				}
+			case "when":
+				flags = strs.Split(kv[1], ",")
+			default:
+				return nil, fmt.Errorf("unknown directive '%s' in template '%s'", kv[0], filePath)
+			}
+		}
+	}
+	tmpl, err := template.New(filepath.Base(filePath)).Funcs(TemplateFuncs()).Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, base := filepath.Split(filepath.FromSlash(output))
+	ext := strs.TrimPrefix(filepath.Ext(base), ".")
+	fileName := strs.TrimSuffix(base, filepath.Ext(base))
+	if fileName == "" {
+		fileName, ext = base, ""
+	}
+	fileType := "go"
+	if ext != "go" {
+		fileType = "template:" + ext
+	}
+
+	return func(g *Generator) {
+		if fileType != "go" {
+			g.AddCreator(fileType, func(base string, path string, name string, overwrite bool, merge bool) file.File {
+				return files.NewRawFile(base, path, name, ext, overwrite, merge)
+			})
+		}
+		g.AddSpec(name,
+			file.NewSpec(fileType).
+				Path(filepath.Clean(dir), nil).
+				Name(fileName, nil).
+				Overwrite(overwrite, nil).
+				Merge(merge, nil).
+				Conditions(func(service types.Service) bool {
+					return service.Generate.Has(flags...)
+				}))
+		g.AddServiceGenerator(name, name+":service", func(file file.File, service types.Service) error {
+			return executeTemplate(file, tmpl, tmpl.Name(), TemplateData{Service: service})
+		})
+		if t := tmpl.Lookup("method"); t != nil {
+			g.AddMethodGenerator(name, name+":method", func(file file.File, service types.Service, method types.Method) error {
+				return executeTemplate(file, tmpl, "method", TemplateData{Service: service, Method: method})
+			})
+		}
+		if t := tmpl.Lookup("entity"); t != nil {
+			g.AddEntityGenerator(name, name+":entity", func(file file.File, service types.Service, entity types.Entity) error {
+				return executeTemplate(file, tmpl, "entity", TemplateData{Service: service, Entity: entity})
+			})
+		}
+		if t := tmpl.Lookup("argumentsGroup"); t != nil {
+			g.AddArgumentsGroupGenerator(name, name+":argumentsGroup", func(file file.File, service types.Service, argsGroup types.ArgumentsGroup) error {
+				return executeTemplate(file, tmpl, "argumentsGroup", TemplateData{Service: service, ArgumentsGroup: argsGroup})
+			})
+		}
+		if t := tmpl.Lookup("enum"); t != nil {
+			g.AddEnumGenerator(name, name+":enum", func(file file.File, service types.Service, enum types.Enum) error {
+				return executeTemplate(file, tmpl, "enum", TemplateData{Service: service, Enum: enum})
+			})
+		}
+	}, nil
+}
+
+func executeTemplate(file file.File, tmpl *template.Template, name string, data TemplateData) error {
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	tmpl.Funcs(template.FuncMap{
+		"addImport": func(alias string, path string) string {
+			file.AddImport(alias, path)
+			return ""
+		},
+	})
+	var b strs.Builder
+	if err := tmpl.ExecuteTemplate(&b, name, &data); err != nil {
+		return err
+	}
+	out := b.String()
+	if strs.TrimSpace(out) == "" {
+		return nil
+	}
+	file.Ps(strs.Split(strs.TrimSuffix(out, "\n"), "\n")...)
+	return nil
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	strs "strings"
+	"sync"
+	"testing"
+
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func TestTemplateSpecs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goms-templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := `{{/* goms: name=audit */}}{{addImport "" "log"}}
+func Audit(method string) {
+	log.Printf("{{.Service.Name}}: %s", method)
+}
+{{define "method"}}{{addImport "str" "strings"}}
+func Audit{{.Method.Name}}() { Audit(str.ToLower("{{.Method.Name}}")) }
+{{end}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "audit.go.tmpl"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opt, err := TemplateSpecs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(append([]GeneratorOption{opt}, builtInFileCreators...)...)
+
+	services := []types.Service{
+		{Name: "Users", Path: dir, Methods: []types.Method{{Name: "Create"}, {Name: "Delete"}}},
+		{Name: "Orders", Path: dir, Methods: []types.Method{{Name: "Place"}}},
+	}
+	var wg sync.WaitGroup
+	for _, service := range services {
+		wg.Add(1)
+		go func(service types.Service) {
+			defer wg.Done()
+			fs, err := g.Generate(service)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			changes, err := fs.Changes()
+			if err != nil || len(changes) != 1 {
+				t.Errorf("%s: got %v, %v", service.Name, changes, err)
+				return
+			}
+			got := string(changes[0].Content)
+			want := []string{`"log"`, `str "strings"`, `log.Printf("` + service.Name + `: %s", method)`}
+			for _, method := range service.Methods {
+				want = append(want, "func Audit"+method.Name+"() { Audit(str.ToLower(\""+method.Name+"\")) }")
+			}
+			for _, w := range want {
+				if !strs.Contains(got, w) {
+					t.Errorf("%s: expected %q in:\n%s", service.Name, w, got)
+				}
+			}
+		}(service)
+	}
+	wg.Wait()
+}
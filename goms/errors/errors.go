@@ -1,6 +1,77 @@
 package errors
 
-import "fmt"
+import (
+	goerrors "errors"
+	"fmt"
+	"strings"
+)
+
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindInvalidArgument
+	KindNotFound
+	KindConflict
+	KindUnauthenticated
+	KindPermissionDenied
+	KindUnavailable
+	KindUnimplemented
+	KindInternal
+)
+
+var kindNames = map[Kind]string{
+	KindUnknown:          "unknown",
+	KindInvalidArgument:  "invalid-argument",
+	KindNotFound:         "not-found",
+	KindConflict:         "conflict",
+	KindUnauthenticated:  "unauthenticated",
+	KindPermissionDenied: "permission-denied",
+	KindUnavailable:      "unavailable",
+	KindUnimplemented:    "unimplemented",
+	KindInternal:         "internal",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return kindNames[KindUnknown]
+}
+
+func ParseKind(s string) Kind {
+	for kind, name := range kindNames {
+		if name == s {
+			return kind
+		}
+	}
+	return KindUnknown
+}
+
+type Classifier interface {
+	Kind() Kind
+}
+
+func KindOf(err error) Kind {
+	var c Classifier
+	if goerrors.As(err, &c) {
+		return c.Kind()
+	}
+	return KindUnknown
+}
+
+type Error struct {
+	Class   Kind
+	Message string
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+func (err *Error) Kind() Kind {
+	return err.Class
+}
 
 type ErrMethodNotImplemented struct {
 	Service string
@@ -11,6 +82,10 @@ func (err *ErrMethodNotImplemented) Error() string {
 	return fmt.Sprintf("method '%s' is not implemented in service '%s'", err.Method, err.Service)
 }
 
+func (err *ErrMethodNotImplemented) Kind() Kind {
+	return KindUnimplemented
+}
+
 type ErrInvalidRequest struct {
 	Service string
 	Method  string
@@ -20,6 +95,10 @@ func (err *ErrInvalidRequest) Error() string {
 	return fmt.Sprintf("invalid request received for '%s' method in '%s' service", err.Method, err.Service)
 }
 
+func (err *ErrInvalidRequest) Kind() Kind {
+	return KindInvalidArgument
+}
+
 type ErrInvalidResponse struct {
 	Service string
 	Method  string
@@ -29,6 +108,40 @@ func (err *ErrInvalidResponse) Error() string {
 	return fmt.Sprintf("invalid response returned from '%s' method in '%s' service", err.Method, err.Service)
 }
 
+func (err *ErrInvalidResponse) Kind() Kind {
+	return KindInternal
+}
+
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type ErrValidation struct {
+	Violations []FieldViolation
+}
+
+func (err *ErrValidation) Error() string {
+	messages := make([]string, len(err.Violations))
+	for i, v := range err.Violations {
+		messages[i] = v.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+func (err *ErrValidation) Kind() Kind {
+	return KindInvalidArgument
+}
+
+func (err *ErrValidation) Add(field string, rule string, message string) {
+	err.Violations = append(err.Violations, FieldViolation{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+	})
+}
+
 func MethodNotImplemented(service string, method string) error {
 	return &ErrMethodNotImplemented{
 		Service: service,
@@ -49,3 +162,40 @@ func InvalidResponse(service string, method string) error {
 		Method:  method,
 	}
 }
+
+func Validation(field string, rule string, message string) error {
+	err := &ErrValidation{}
+	err.Add(field, rule, message)
+	return err
+}
+
+func New(kind Kind, message string) error {
+	return &Error{
+		Class:   kind,
+		Message: message,
+	}
+}
+
+func NotFound(message string) error {
+	return New(KindNotFound, message)
+}
+
+func Conflict(message string) error {
+	return New(KindConflict, message)
+}
+
+func Unauthenticated(message string) error {
+	return New(KindUnauthenticated, message)
+}
+
+func PermissionDenied(message string) error {
+	return New(KindPermissionDenied, message)
+}
+
+func Unavailable(message string) error {
+	return New(KindUnavailable, message)
+}
+
+func Internal(message string) error {
+	return New(KindInternal, message)
+}
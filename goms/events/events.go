@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/wlMalk/goms/goms/correlation"
+	"github.com/wlMalk/goms/goms/request"
+
+	"github.com/rs/xid"
+)
+
+// Event is published after a successful call. It only holds serializable
+// values, so it can be stored as is in an outbox and relayed later, and its
+// ID lets consumers drop the events relayed more than once.
+type Event struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Source        string          `json:"source"`
+	Time          time.Time       `json:"time"`
+	CorrelationID string          `json:"correlationID,omitempty"`
+	RequestID     string          `json:"requestID,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// New creates an event carrying data as JSON, along with the correlation and
+// request IDs found in ctx.
+func New(ctx context.Context, source string, name string, data interface{}) (*Event, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		ID:            xid.New().String(),
+		Name:          name,
+		Source:        source,
+		Time:          time.Now().UTC(),
+		CorrelationID: correlation.GetCorrelationID(ctx),
+		RequestID:     request.GetRequestID(ctx),
+		Data:          b,
+	}, nil
+}
+
+func (e *Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Publisher publishes the events of a call together. An outbox publisher
+// would store them in the transaction found in ctx, if any.
+type Publisher interface {
+	Publish(ctx context.Context, events ...*Event) error
+}
+
+type PublisherFunc func(ctx context.Context, events ...*Event) error
+
+func (f PublisherFunc) Publish(ctx context.Context, events ...*Event) error {
+	return f(ctx, events...)
+}
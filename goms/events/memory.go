@@ -0,0 +1,39 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher keeps the published events in memory, to be inspected in
+// tests.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, events ...*Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// Events returns the events published so far, in order.
+func (p *MemoryPublisher) Events() []*Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	events := make([]*Event, len(p.events))
+	copy(events, p.events)
+	return events
+}
+
+func (p *MemoryPublisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = nil
+}
@@ -2,18 +2,24 @@ package grpc
 
 import (
 	"context"
+	goerrors "errors"
 	"net"
 	"strings"
 
 	"github.com/wlMalk/goms/goms/correlation"
+	"github.com/wlMalk/goms/goms/errors"
 	"github.com/wlMalk/goms/goms/log/contextual"
 	"github.com/wlMalk/goms/goms/request"
 	"github.com/wlMalk/goms/goms/service"
 
+	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	kit_grpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -36,11 +42,98 @@ func (s *Server) GracefulStop() {
 	s.Server.GracefulStop()
 }
 
+var kindCodes = map[errors.Kind]codes.Code{
+	errors.KindInvalidArgument:  codes.InvalidArgument,
+	errors.KindNotFound:         codes.NotFound,
+	errors.KindConflict:         codes.AlreadyExists,
+	errors.KindUnauthenticated:  codes.Unauthenticated,
+	errors.KindPermissionDenied: codes.PermissionDenied,
+	errors.KindUnavailable:      codes.Unavailable,
+	errors.KindUnimplemented:    codes.Unimplemented,
+	errors.KindInternal:         codes.Internal,
+}
+
+func Code(kind errors.Kind) codes.Code {
+	if code, ok := kindCodes[kind]; ok {
+		return code
+	}
+	return codes.Unknown
+}
+
+func KindOfCode(code codes.Code) errors.Kind {
+	if code == codes.Aborted {
+		return errors.KindConflict
+	}
+	for kind, c := range kindCodes {
+		if c == code {
+			return kind
+		}
+	}
+	return errors.KindUnknown
+}
+
+func EncodeError(err error) error {
+	kind := errors.KindOf(err)
+	if kind == errors.KindUnknown {
+		return err
+	}
+	st := status.New(Code(kind), err.Error())
+	var verr *errors.ErrValidation
+	if !goerrors.As(err, &verr) {
+		return st.Err()
+	}
+	br := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Message,
+			Reason:      v.Rule,
+		})
+	}
+	if withDetails, err := st.WithDetails(br); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func DecodeError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	for _, detail := range st.Details() {
+		br, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		verr := &errors.ErrValidation{}
+		for _, v := range br.FieldViolations {
+			verr.Add(v.Field, v.Reason, v.Description)
+		}
+		return verr
+	}
+	kind := KindOfCode(st.Code())
+	if kind == errors.KindUnknown {
+		return err
+	}
+	return errors.New(kind, st.Message())
+}
+
+func ErrorDecoder(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		response, err := next(ctx, request)
+		if err != nil {
+			return nil, DecodeError(err)
+		}
+		return response, nil
+	}
+}
+
 func LoggerInjector(logger log.Logger) kit_grpc.ServerRequestFunc {
 	return func(ctx context.Context, md metadata.MD) context.Context {
 		requestID := request.GetRequestID(ctx)
-		logger = contextual.ContextualLogger(ctx, logger, "request-id", requestID)
-		return contextual.SetLogger(ctx, logger)
+		l := contextual.ContextualLogger(ctx, logger, "request-id", requestID)
+		return contextual.SetLogger(ctx, l)
 	}
 }
 
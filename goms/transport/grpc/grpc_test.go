@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wlMalk/goms/goms/errors"
+)
+
+func TestEncodeDecodeError(t *testing.T) {
+	verr := &errors.ErrValidation{}
+	verr.Add("name", "required", "name is required")
+	verr.Add("name", "min-len", "name is too short")
+	got, ok := DecodeError(EncodeError(verr)).(*errors.ErrValidation)
+	if !ok || !reflect.DeepEqual(got.Violations, verr.Violations) {
+		t.Errorf("got %v, want %v", got, verr)
+	}
+
+	for kind := range kindCodes {
+		err := DecodeError(EncodeError(errors.New(kind, "failed")))
+		if errors.KindOf(err) != kind || err.Error() != "failed" {
+			t.Errorf("%s: got %v of kind %s", kind, err, errors.KindOf(err))
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"reflect"
+	"unsafe"
+
+	kit_grpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// NopRequestDecoder and NopResponseEncoder let a go-kit server call the
+// endpoint of a streaming method, whose handler converts the messages itself.
+func NopRequestDecoder(_ context.Context, request interface{}) (interface{}, error) {
+	return request, nil
+}
+
+func NopResponseEncoder(_ context.Context, response interface{}) (interface{}, error) {
+	return response, nil
+}
+
+// StreamClient applies the request and response functions of go-kit client
+// options to the calls of streaming methods, which go-kit clients cannot make.
+type StreamClient struct {
+	before []kit_grpc.ClientRequestFunc
+	after  []kit_grpc.ClientResponseFunc
+}
+
+func NewStreamClient(opts ...kit_grpc.ClientOption) *StreamClient {
+	client := &kit_grpc.Client{}
+	for _, opt := range opts {
+		opt(client)
+	}
+	// go-kit keeps the functions set by the options unexported
+	v := reflect.ValueOf(client).Elem()
+	return &StreamClient{
+		before: *(*[]kit_grpc.ClientRequestFunc)(unsafe.Pointer(v.FieldByName("before").UnsafeAddr())),
+		after:  *(*[]kit_grpc.ClientResponseFunc)(unsafe.Pointer(v.FieldByName("after").UnsafeAddr())),
+	}
+}
+
+func (c *StreamClient) Before(ctx context.Context) context.Context {
+	md := &metadata.MD{}
+	for _, f := range c.before {
+		ctx = f(ctx, md)
+	}
+	return metadata.NewOutgoingContext(ctx, *md)
+}
+
+func (c *StreamClient) After(ctx context.Context, header metadata.MD, trailer metadata.MD) context.Context {
+	for _, f := range c.after {
+		ctx = f(ctx, header, trailer)
+	}
+	return ctx
+}
@@ -3,10 +3,13 @@ package http
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
+	"io/ioutil"
 	"net/http"
 	"strings"
 
 	"github.com/wlMalk/goms/goms/correlation"
+	"github.com/wlMalk/goms/goms/errors"
 	"github.com/wlMalk/goms/goms/log/contextual"
 	"github.com/wlMalk/goms/goms/request"
 	"github.com/wlMalk/goms/goms/service"
@@ -20,6 +23,101 @@ func HTTPResponseEncoder(_ context.Context, w http.ResponseWriter, response inte
 	return json.NewEncoder(w).Encode(response)
 }
 
+type Problem struct {
+	Type       string                  `json:"type"`
+	Title      string                  `json:"title"`
+	Status     int                     `json:"status"`
+	Kind       string                  `json:"kind,omitempty"`
+	Detail     string                  `json:"detail,omitempty"`
+	Violations []errors.FieldViolation `json:"violations,omitempty"`
+}
+
+var kindStatuses = map[errors.Kind]int{
+	errors.KindInvalidArgument:  http.StatusBadRequest,
+	errors.KindNotFound:         http.StatusNotFound,
+	errors.KindConflict:         http.StatusConflict,
+	errors.KindUnauthenticated:  http.StatusUnauthorized,
+	errors.KindPermissionDenied: http.StatusForbidden,
+	errors.KindUnavailable:      http.StatusServiceUnavailable,
+	errors.KindUnimplemented:    http.StatusNotImplemented,
+	errors.KindInternal:         http.StatusInternalServerError,
+}
+
+func StatusCode(kind errors.Kind) int {
+	if code, ok := kindStatuses[kind]; ok {
+		return code
+	}
+	return http.StatusInternalServerError
+}
+
+func KindOfStatus(code int) errors.Kind {
+	for kind, c := range kindStatuses {
+		if c == code {
+			return kind
+		}
+	}
+	if code == http.StatusTooManyRequests {
+		return errors.KindUnavailable
+	}
+	return errors.KindUnknown
+}
+
+func ErrorEncoder(ctx context.Context, err error, w http.ResponseWriter) {
+	kind := errors.KindOf(err)
+	if kind == errors.KindUnknown {
+		kit_http.DefaultErrorEncoder(ctx, err, w)
+		return
+	}
+	code := StatusCode(kind)
+	problem := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+		Kind:   kind.String(),
+		Detail: err.Error(),
+	}
+	var verr *errors.ErrValidation
+	if goerrors.As(err, &verr) {
+		problem.Violations = verr.Violations
+	}
+	w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(problem)
+}
+
+func ErrorDecoder(dec kit_http.DecodeResponseFunc) kit_http.DecodeResponseFunc {
+	return func(ctx context.Context, res *http.Response) (interface{}, error) {
+		if res.StatusCode < 400 {
+			return dec(ctx, res)
+		}
+		defer res.Body.Close()
+		return nil, DecodeError(res)
+	}
+}
+
+func DecodeError(res *http.Response) error {
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	problem := &Problem{}
+	if json.Unmarshal(body, problem) != nil || problem.Status == 0 {
+		message := strings.TrimSpace(string(body))
+		if len(message) == 0 {
+			message = http.StatusText(res.StatusCode)
+		}
+		return errors.New(KindOfStatus(res.StatusCode), message)
+	}
+	if len(problem.Violations) > 0 {
+		return &errors.ErrValidation{Violations: problem.Violations}
+	}
+	kind := errors.ParseKind(problem.Kind)
+	if kind == errors.KindUnknown {
+		kind = KindOfStatus(problem.Status)
+	}
+	return errors.New(kind, problem.Detail)
+}
+
 type Server struct {
 	router Router
 	http.Server
@@ -73,8 +171,8 @@ func FormatURI(uri string, pairs ...string) string {
 func LoggerInjector(logger log.Logger) kit_http.RequestFunc {
 	return func(ctx context.Context, r *http.Request) context.Context {
 		requestID := request.GetRequestID(ctx)
-		logger = contextual.ContextualLogger(ctx, logger, "request-id", requestID)
-		return contextual.SetLogger(ctx, logger)
+		l := contextual.ContextualLogger(ctx, logger, "request-id", requestID)
+		return contextual.SetLogger(ctx, l)
 	}
 }
 
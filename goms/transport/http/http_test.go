@@ -0,0 +1,27 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/wlMalk/goms/goms/errors"
+)
+
+func TestKindOfStatus(t *testing.T) {
+	for kind, code := range kindStatuses {
+		if got := KindOfStatus(code); got != kind {
+			t.Errorf("%d: got %s, want %s", code, got, kind)
+		}
+	}
+	tests := map[int]errors.Kind{
+		http.StatusTooManyRequests:     errors.KindUnavailable,
+		http.StatusTeapot:              errors.KindUnknown,
+		http.StatusUnprocessableEntity: errors.KindUnknown,
+		http.StatusBadGateway:          errors.KindUnknown,
+	}
+	for code, want := range tests {
+		if got := KindOfStatus(code); got != want {
+			t.Errorf("%d: got %s, want %s", code, got, want)
+		}
+	}
+}
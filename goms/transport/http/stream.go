@@ -0,0 +1,311 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	kit_http "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/websocket"
+)
+
+type StreamWriter interface {
+	Send(v interface{}) error
+}
+
+type StreamReader interface {
+	Recv(v interface{}) error
+	Close() error
+}
+
+const contextStreamKey contextKeyType = "stream"
+
+// WebSocketUpgrader rejects cross-origin connections by default. Set its
+// CheckOrigin to accept them.
+var WebSocketUpgrader = &websocket.Upgrader{}
+
+func ServerSentEvents(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if flusher, ok := w.(http.Flusher); ok {
+			r = r.WithContext(context.WithValue(r.Context(), contextStreamKey, flusher))
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// WebSocket reads the first message from the client as the body of the
+// request, and sends the status code first, followed by either the values of
+// the stream or the error body.
+func WebSocket(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := WebSocketUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, body, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					cancel()
+					return
+				}
+			}
+		}()
+		ws := &webSocketWriter{conn: conn, header: http.Header{}}
+		r = r.WithContext(context.WithValue(ctx, contextStreamKey, ws))
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		handler.ServeHTTP(ws, r)
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	})
+}
+
+// NewStreamWriter works only in handlers wrapped by ServerSentEvents or WebSocket.
+func NewStreamWriter(ctx context.Context, w http.ResponseWriter) (StreamWriter, error) {
+	switch s := ctx.Value(contextStreamKey).(type) {
+	case *webSocketWriter:
+		w.WriteHeader(http.StatusOK)
+		return s, nil
+	case http.Flusher:
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		s.Flush()
+		return &sseWriter{w: w, flusher: s}, nil
+	}
+	return nil, goerrors.New("response cannot be streamed")
+}
+
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s *sseWriter) Send(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.w, "data: %s\n\n", b)
+	if err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+type webSocketWriter struct {
+	conn        *websocket.Conn
+	header      http.Header
+	wroteHeader bool
+}
+
+func (s *webSocketWriter) Header() http.Header {
+	return s.header
+}
+
+func (s *webSocketWriter) WriteHeader(code int) {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+	s.conn.WriteMessage(websocket.TextMessage, []byte(strconv.Itoa(code)))
+}
+
+func (s *webSocketWriter) Write(b []byte) (int, error) {
+	s.WriteHeader(http.StatusOK)
+	err := s.conn.WriteMessage(websocket.TextMessage, b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (s *webSocketWriter) Send(v interface{}) error {
+	return s.conn.WriteJSON(v)
+}
+
+// WebSocketClient has to be used with BufferedStream(true).
+func WebSocketClient(dialer *websocket.Dialer) kit_http.HTTPClient {
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	return &webSocketClient{dialer: dialer}
+}
+
+type webSocketClient struct {
+	dialer *websocket.Dialer
+}
+
+func (c *webSocketClient) Do(req *http.Request) (*http.Response, error) {
+	u := *req.URL
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	header := http.Header{}
+	for name, values := range req.Header {
+		switch http.CanonicalHeaderKey(name) {
+		case "Upgrade", "Connection", "Content-Length", "Content-Type":
+		default:
+			if !strings.HasPrefix(http.CanonicalHeaderKey(name), "Sec-Websocket-") {
+				header[name] = values
+			}
+		}
+	}
+	conn, res, err := c.dialer.DialContext(req.Context(), u.String(), header)
+	if err != nil {
+		if err == websocket.ErrBadHandshake && res != nil {
+			return res, nil
+		}
+		return nil, err
+	}
+	fail := func(err error) (*http.Response, error) {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, body); err != nil {
+		return fail(err)
+	}
+	_, status, err := conn.ReadMessage()
+	if err != nil {
+		return fail(err)
+	}
+	code, err := strconv.Atoi(string(status))
+	if err != nil {
+		return fail(fmt.Errorf("invalid status '%s' in websocket stream", status))
+	}
+	res = &http.Response{
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Request:    req,
+	}
+	if code >= 400 {
+		_, body, err := conn.ReadMessage()
+		conn.Close()
+		if err != nil {
+			body = nil
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return res, nil
+	}
+	res.Body = &webSocketBody{conn: conn}
+	return res, nil
+}
+
+type webSocketBody struct {
+	conn *websocket.Conn
+	r    io.Reader
+}
+
+func (b *webSocketBody) Read(p []byte) (int, error) {
+	for {
+		if b.r == nil {
+			_, r, err := b.conn.NextReader()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+			b.r = r
+		}
+		n, err := b.r.Read(p)
+		if err == io.EOF {
+			b.r = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (b *webSocketBody) Close() error {
+	return b.conn.Close()
+}
+
+// NewStreamReader closes the body of res once ctx is done.
+func NewStreamReader(ctx context.Context, res *http.Response) StreamReader {
+	s := &streamReader{body: res.Body, done: make(chan struct{})}
+	if strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+		s.events = bufio.NewReader(res.Body)
+	} else {
+		s.values = json.NewDecoder(res.Body)
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s
+}
+
+type streamReader struct {
+	body   io.ReadCloser
+	events *bufio.Reader
+	values *json.Decoder
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (s *streamReader) Recv(v interface{}) error {
+	if s.values != nil {
+		return s.values.Decode(v)
+	}
+	var data []string
+	for {
+		line, err := s.events.ReadString('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if len(data) > 0 {
+				return json.Unmarshal([]byte(strings.Join(data, "\n")), v)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "data:") {
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
+
+func (s *streamReader) Close() (err error) {
+	s.once.Do(func() {
+		close(s.done)
+		err = s.body.Close()
+	})
+	return
+}
@@ -0,0 +1,274 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/wlMalk/goms/goms/errors"
+
+	kit_http "github.com/go-kit/kit/transport/http"
+	kit_jsonrpc "github.com/go-kit/kit/transport/http/jsonrpc"
+)
+
+// Server error codes, in the range the JSON-RPC 2.0 specification reserves
+// for implementation-defined errors.
+const (
+	NotFoundError         int = -32001
+	ConflictError         int = -32002
+	UnauthenticatedError  int = -32003
+	PermissionDeniedError int = -32004
+	UnavailableError      int = -32005
+)
+
+var kindCodes = map[errors.Kind]int{
+	errors.KindInvalidArgument:  kit_jsonrpc.InvalidParamsError,
+	errors.KindNotFound:         NotFoundError,
+	errors.KindConflict:         ConflictError,
+	errors.KindUnauthenticated:  UnauthenticatedError,
+	errors.KindPermissionDenied: PermissionDeniedError,
+	errors.KindUnavailable:      UnavailableError,
+	errors.KindUnimplemented:    kit_jsonrpc.MethodNotFoundError,
+	errors.KindInternal:         kit_jsonrpc.InternalError,
+}
+
+func Code(kind errors.Kind) int {
+	if code, ok := kindCodes[kind]; ok {
+		return code
+	}
+	return kit_jsonrpc.InternalError
+}
+
+func KindOfCode(code int) errors.Kind {
+	for kind, c := range kindCodes {
+		if c == code {
+			return kind
+		}
+	}
+	return errors.KindUnknown
+}
+
+// ErrorData is the data of the errors returned for goms errors.
+type ErrorData struct {
+	Kind       string                  `json:"kind,omitempty"`
+	Violations []errors.FieldViolation `json:"violations,omitempty"`
+}
+
+func EncodeError(err error) *kit_jsonrpc.Error {
+	var e kit_jsonrpc.Error
+	if goerrors.As(err, &e) {
+		return &e
+	}
+	kind := errors.KindOf(err)
+	if kind == errors.KindUnknown {
+		code := kit_jsonrpc.InternalError
+		var coder kit_jsonrpc.ErrorCoder
+		if goerrors.As(err, &coder) {
+			code = coder.ErrorCode()
+		}
+		return &kit_jsonrpc.Error{Code: code, Message: err.Error()}
+	}
+	data := &ErrorData{Kind: kind.String()}
+	var verr *errors.ErrValidation
+	if goerrors.As(err, &verr) {
+		data.Violations = verr.Violations
+	}
+	return &kit_jsonrpc.Error{Code: Code(kind), Message: err.Error(), Data: data}
+}
+
+func DecodeError(e *kit_jsonrpc.Error) error {
+	if e == nil {
+		return nil
+	}
+	data := &ErrorData{}
+	if e.Data != nil {
+		if b, err := json.Marshal(e.Data); err == nil {
+			json.Unmarshal(b, data)
+		}
+	}
+	if len(data.Violations) > 0 {
+		return &errors.ErrValidation{Violations: data.Violations}
+	}
+	kind := errors.ParseKind(data.Kind)
+	if kind == errors.KindUnknown {
+		kind = KindOfCode(e.Code)
+	}
+	if kind == errors.KindUnknown {
+		return *e
+	}
+	return errors.New(kind, e.Message)
+}
+
+// DecodeParams decodes the params of a request into v. Params have to be
+// passed by name.
+func DecodeParams(params json.RawMessage, v interface{}) error {
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return kit_jsonrpc.Error{Code: kit_jsonrpc.InvalidParamsError, Message: err.Error()}
+	}
+	return nil
+}
+
+type method struct {
+	codec  kit_jsonrpc.EndpointCodec
+	before []kit_http.RequestFunc
+	after  []kit_http.ServerResponseFunc
+}
+
+type ServerOption func(*method)
+
+func ServerBefore(before ...kit_http.RequestFunc) ServerOption {
+	return func(m *method) { m.before = append(m.before, before...) }
+}
+
+func ServerAfter(after ...kit_http.ServerResponseFunc) ServerOption {
+	return func(m *method) { m.after = append(m.after, after...) }
+}
+
+// Server serves all of its registered methods on a single endpoint, taking
+// both single and batch requests.
+type Server struct {
+	methods map[string]*method
+	http.Server
+}
+
+func NewServer() *Server {
+	s := &Server{methods: map[string]*method{}}
+	s.Server.Handler = s
+	return s
+}
+
+func (s *Server) RegisterMethod(name string, codec kit_jsonrpc.EndpointCodec, opts ...ServerOption) {
+	m := &method{codec: codec}
+	for _, opt := range opts {
+		opt(m)
+	}
+	s.methods[name] = m
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeResponse(w, errorResponse(nil, kit_jsonrpc.ParseError, err.Error()))
+		return
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		res := s.serve(r, w, body)
+		if res == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeResponse(w, res)
+		return
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		writeResponse(w, errorResponse(nil, kit_jsonrpc.ParseError, err.Error()))
+		return
+	}
+	if len(batch) == 0 {
+		writeResponse(w, errorResponse(nil, kit_jsonrpc.InvalidRequestError, "empty batch"))
+		return
+	}
+	responses := make([]*kit_jsonrpc.Response, 0, len(batch))
+	for _, msg := range batch {
+		if res := s.serve(r, w, msg); res != nil {
+			responses = append(responses, res)
+		}
+	}
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeResponse(w, responses)
+}
+
+// serve handles a single request, returning nil for notifications.
+func (s *Server) serve(r *http.Request, w http.ResponseWriter, msg json.RawMessage) *kit_jsonrpc.Response {
+	var req kit_jsonrpc.Request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		var serr *json.SyntaxError
+		if goerrors.As(err, &serr) {
+			return errorResponse(nil, kit_jsonrpc.ParseError, err.Error())
+		}
+		return errorResponse(nil, kit_jsonrpc.InvalidRequestError, err.Error())
+	}
+	// go-kit clients leave the version empty.
+	if (req.JSONRPC != "" && req.JSONRPC != kit_jsonrpc.Version) || req.Method == "" {
+		return errorResponse(req.ID, kit_jsonrpc.InvalidRequestError, kit_jsonrpc.ErrorMessage(kit_jsonrpc.InvalidRequestError))
+	}
+	res := s.call(r, w, req)
+	if req.ID == nil {
+		return nil
+	}
+	return res
+}
+
+func (s *Server) call(r *http.Request, w http.ResponseWriter, req kit_jsonrpc.Request) *kit_jsonrpc.Response {
+	m, ok := s.methods[req.Method]
+	if !ok {
+		return errorResponse(req.ID, kit_jsonrpc.MethodNotFoundError, "method '"+req.Method+"' not found")
+	}
+	ctx := r.Context()
+	for _, f := range m.before {
+		ctx = f(ctx, r)
+	}
+	request, err := m.codec.Decode(ctx, req.Params)
+	if err != nil {
+		var coder kit_jsonrpc.ErrorCoder
+		if goerrors.As(err, &coder) {
+			return errorResponse(req.ID, coder.ErrorCode(), err.Error())
+		}
+		return errorResponse(req.ID, kit_jsonrpc.InvalidParamsError, err.Error())
+	}
+	response, err := m.codec.Endpoint(ctx, request)
+	if err != nil {
+		return &kit_jsonrpc.Response{JSONRPC: kit_jsonrpc.Version, Error: EncodeError(err), ID: req.ID}
+	}
+	for _, f := range m.after {
+		ctx = f(ctx, w)
+	}
+	result, err := m.codec.Encode(ctx, response)
+	if err != nil {
+		return &kit_jsonrpc.Response{JSONRPC: kit_jsonrpc.Version, Error: EncodeError(err), ID: req.ID}
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	return &kit_jsonrpc.Response{JSONRPC: kit_jsonrpc.Version, Result: result, ID: req.ID}
+}
+
+func errorResponse(id *kit_jsonrpc.RequestID, code int, message string) *kit_jsonrpc.Response {
+	return &kit_jsonrpc.Response{
+		JSONRPC: kit_jsonrpc.Version,
+		Error:   &kit_jsonrpc.Error{Code: code, Message: message},
+		ID:      id,
+	}
+}
+
+func writeResponse(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", kit_jsonrpc.ContentType)
+	json.NewEncoder(w).Encode(v)
+}
+
+// ErrorDecoder makes the errors of a client returned as goms errors.
+func ErrorDecoder(dec kit_jsonrpc.DecodeResponseFunc) kit_jsonrpc.DecodeResponseFunc {
+	return func(ctx context.Context, res kit_jsonrpc.Response) (interface{}, error) {
+		if res.Error != nil {
+			return nil, DecodeError(res.Error)
+		}
+		return dec(ctx, res)
+	}
+}
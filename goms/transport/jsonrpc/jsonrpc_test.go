@@ -0,0 +1,224 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wlMalk/goms/goms/errors"
+
+	kit_jsonrpc "github.com/go-kit/kit/transport/http/jsonrpc"
+)
+
+type testParams struct {
+	A    int    `json:"a"`
+	B    int    `json:"b"`
+	Kind string `json:"kind"`
+}
+
+func testServer(calls *int) *Server {
+	s := NewServer()
+	decode := func(_ context.Context, params json.RawMessage) (interface{}, error) {
+		p := &testParams{}
+		return p, DecodeParams(params, p)
+	}
+	encode := func(_ context.Context, response interface{}) (json.RawMessage, error) {
+		return json.Marshal(response)
+	}
+	s.RegisterMethod("add", kit_jsonrpc.EndpointCodec{
+		Endpoint: func(_ context.Context, request interface{}) (interface{}, error) {
+			*calls++
+			p := request.(*testParams)
+			return p.A + p.B, nil
+		},
+		Decode: decode,
+		Encode: encode,
+	})
+	s.RegisterMethod("fail", kit_jsonrpc.EndpointCodec{
+		Endpoint: func(_ context.Context, request interface{}) (interface{}, error) {
+			*calls++
+			kind := errors.ParseKind(request.(*testParams).Kind)
+			if kind == errors.KindInvalidArgument {
+				return nil, errors.Validation("a", "required", "a is required")
+			}
+			if kind == errors.KindUnknown {
+				return nil, json.Unmarshal([]byte("{"), &struct{}{})
+			}
+			return nil, errors.New(kind, "failed")
+		},
+		Decode: decode,
+		Encode: encode,
+	})
+	return s
+}
+
+// testPost returns the status and the decoded body of the response, without
+// the messages of the errors.
+func testPost(t *testing.T, s *Server, body string) (int, interface{}) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	if rec.Body.Len() == 0 {
+		return rec.Code, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("%s: invalid response body %q: %v", body, rec.Body.String(), err)
+	}
+	responses, ok := v.([]interface{})
+	if !ok {
+		responses = []interface{}{v}
+	}
+	for _, res := range responses {
+		if e, ok := res.(map[string]interface{})["error"].(map[string]interface{}); ok {
+			delete(e, "message")
+		}
+	}
+	return rec.Code, v
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name:   "call",
+			body:   `{"jsonrpc":"2.0","method":"add","params":{"a":1,"b":2},"id":1}`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","result":3,"id":1}`,
+		},
+		{
+			name:   "call without version",
+			body:   `{"method":"add","params":{"a":1,"b":2},"id":"x"}`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","result":3,"id":"x"}`,
+		},
+		{
+			name:   "notification",
+			body:   `{"jsonrpc":"2.0","method":"add","params":{"a":1,"b":2}}`,
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "batch",
+			body:   `[{"jsonrpc":"2.0","method":"add","params":{"a":1,"b":2},"id":1},{"jsonrpc":"2.0","method":"add","params":{"a":3,"b":4}},{"jsonrpc":"2.0","method":"missing","id":2},{"jsonrpc":"2.0","method":"add","params":{"a":"x"},"id":3}]`,
+			status: http.StatusOK,
+			want:   `[{"jsonrpc":"2.0","result":3,"id":1},{"jsonrpc":"2.0","error":{"code":-32601},"id":2},{"jsonrpc":"2.0","error":{"code":-32602},"id":3}]`,
+		},
+		{
+			name:   "batch of notifications",
+			body:   `[{"jsonrpc":"2.0","method":"add"},{"jsonrpc":"2.0","method":"missing"}]`,
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "batch with invalid requests",
+			body:   `[1,{"jsonrpc":"1.0","method":"add","id":1}]`,
+			status: http.StatusOK,
+			want:   `[{"jsonrpc":"2.0","error":{"code":-32600},"id":null},{"jsonrpc":"2.0","error":{"code":-32600},"id":1}]`,
+		},
+		{
+			name:   "empty batch",
+			body:   `[]`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","error":{"code":-32600},"id":null}`,
+		},
+		{
+			name:   "parse error",
+			body:   `{"jsonrpc":"2.0","method":`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","error":{"code":-32700},"id":null}`,
+		},
+		{
+			name:   "batch parse error",
+			body:   `[{"jsonrpc":"2.0","method":"add","id":1},`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","error":{"code":-32700},"id":null}`,
+		},
+		{
+			name:   "invalid request",
+			body:   `{"jsonrpc":"2.0","method":1,"id":1}`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","error":{"code":-32600},"id":null}`,
+		},
+		{
+			name:   "missing method",
+			body:   `{"jsonrpc":"2.0","id":1}`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","error":{"code":-32600},"id":1}`,
+		},
+		{
+			name:   "validation error",
+			body:   `{"jsonrpc":"2.0","method":"fail","params":{"kind":"invalid-argument"},"id":1}`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","error":{"code":-32602,"data":{"kind":"invalid-argument","violations":[{"field":"a","rule":"required","message":"a is required"}]}},"id":1}`,
+		},
+		{
+			name:   "unknown error",
+			body:   `{"jsonrpc":"2.0","method":"fail","params":{"kind":"unknown"},"id":1}`,
+			status: http.StatusOK,
+			want:   `{"jsonrpc":"2.0","error":{"code":-32603},"id":1}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls int
+			status, got := testPost(t, testServer(&calls), test.body)
+			if status != test.status {
+				t.Fatalf("got status %d, want %d", status, test.status)
+			}
+			var want interface{}
+			if test.want != "" {
+				if err := json.Unmarshal([]byte(test.want), &want); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestServeHTTPNotificationsAreCalled(t *testing.T) {
+	var calls int
+	s := testServer(&calls)
+	testPost(t, s, `{"jsonrpc":"2.0","method":"add"}`)
+	testPost(t, s, `[{"jsonrpc":"2.0","method":"add"},{"jsonrpc":"2.0","method":"fail","params":{"kind":"internal"}},{"jsonrpc":"2.0","method":"add","id":1}]`)
+	if calls != 4 {
+		t.Fatalf("got %d calls, want 4", calls)
+	}
+}
+
+func TestServeHTTPMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewServer().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Fatalf("got status %d and Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestServeHTTPErrorKinds(t *testing.T) {
+	for kind, code := range kindCodes {
+		if kind == errors.KindInvalidArgument {
+			continue
+		}
+		var calls int
+		_, got := testPost(t, testServer(&calls), `{"jsonrpc":"2.0","method":"fail","params":{"kind":"`+kind.String()+`"},"id":1}`)
+		e := got.(map[string]interface{})["error"].(map[string]interface{})
+		if int(e["code"].(float64)) != code {
+			t.Errorf("%s: got code %v, want %d", kind, e["code"], code)
+		}
+		if data := e["data"].(map[string]interface{}); data["kind"] != kind.String() {
+			t.Errorf("%s: got kind %v", kind, data["kind"])
+		}
+		if got := DecodeError(&kit_jsonrpc.Error{Code: code, Message: "failed"}); errors.KindOf(got) != kind {
+			t.Errorf("%d: decoded as %s, want %s", code, errors.KindOf(got), kind)
+		}
+	}
+}
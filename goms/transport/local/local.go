@@ -2,7 +2,9 @@ package local
 
 import (
 	"context"
+	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/wlMalk/goms/goms/correlation"
 	"github.com/wlMalk/goms/goms/log/contextual"
@@ -64,6 +66,81 @@ func (c Client) Endpoint() endpoint.Endpoint {
 	}
 }
 
+// StreamEndpoint is like Endpoint but keeps the outgoing context alive until
+// ctx is done or the channels in the response are drained, so that they can
+// still be consumed after the endpoint returns.
+func (c Client) StreamEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		oCtx, cancel := context.WithCancel(context.Background())
+
+		if c.finalizer != nil {
+			defer func() {
+				for _, f := range c.finalizer {
+					f(ctx, err)
+				}
+			}()
+		}
+
+		for _, f := range c.before {
+			oCtx = f(ctx, oCtx, request)
+		}
+
+		response, err = c.endpoint(oCtx, request)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-oCtx.Done():
+			}
+			cancel()
+		}()
+		cancelOnDrain(oCtx, response, cancel)
+		return response, nil
+	}
+}
+
+// cancelOnDrain replaces the receive channels of the response with ones
+// closed after them, calling cancel once all of them are closed.
+func cancelOnDrain(ctx context.Context, response interface{}, cancel context.CancelFunc) {
+	v := reflect.Indirect(reflect.ValueOf(response))
+	if v.Kind() != reflect.Struct || !v.CanSet() {
+		cancel()
+		return
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Chan || field.Type().ChanDir()&reflect.RecvDir == 0 || field.IsNil() || !field.CanSet() {
+			continue
+		}
+		in := reflect.ValueOf(field.Interface())
+		out := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, field.Type().Elem()), 0)
+		field.Set(out)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer out.Close()
+			done := reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+			for {
+				chosen, x, ok := reflect.Select([]reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: in}, done})
+				if chosen == 1 || !ok {
+					return
+				}
+				if chosen, _, _ = reflect.Select([]reflect.SelectCase{{Dir: reflect.SelectSend, Chan: out, Send: x}, done}); chosen == 1 {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+	}()
+}
+
 type RequestFunc func(ctx context.Context, oCtx context.Context, req interface{}) context.Context
 
 type FinalizerFunc func(ctx context.Context, err error)
@@ -71,8 +148,8 @@ type FinalizerFunc func(ctx context.Context, err error)
 func LoggerInjector(logger log.Logger) RequestFunc {
 	return func(ctx context.Context, oCtx context.Context, req interface{}) context.Context {
 		requestID := request.GetRequestID(oCtx)
-		logger = contextual.ContextualLogger(oCtx, logger, "request-id", requestID)
-		return contextual.SetLogger(oCtx, logger)
+		l := contextual.ContextualLogger(oCtx, logger, "request-id", requestID)
+		return contextual.SetLogger(oCtx, l)
 	}
 }
 
@@ -0,0 +1,80 @@
+package mq
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker is a Broker delivering the messages within the process, for
+// tests and services running together. Subjects are matched exactly.
+type MemoryBroker struct {
+	mu   sync.Mutex
+	subs map[string][]*memorySubscription
+	next map[string]int
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subs: map[string][]*memorySubscription{}, next: map[string]int{}}
+}
+
+type memorySubscription struct {
+	broker  *MemoryBroker
+	subject string
+	queue   string
+	handler Handler
+}
+
+func (s *memorySubscription) Unsubscribe() error {
+	b := s.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := b.subs[s.subject]
+	for i, sub := range subs {
+		if sub == s {
+			b.subs[s.subject] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(subject string, queue string, handler Handler) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := &memorySubscription{broker: b, subject: subject, queue: queue, handler: handler}
+	b.subs[subject] = append(b.subs[subject], sub)
+	return sub, nil
+}
+
+// Publish hands a copy of msg to every subscriber in its own goroutine, and
+// to the members of each queue group in turn.
+func (b *MemoryBroker) Publish(ctx context.Context, msg *Message) error {
+	b.mu.Lock()
+	var targets []*memorySubscription
+	queues := map[string][]*memorySubscription{}
+	for _, sub := range b.subs[msg.Subject] {
+		if sub.queue == "" {
+			targets = append(targets, sub)
+		} else {
+			queues[sub.queue] = append(queues[sub.queue], sub)
+		}
+	}
+	for queue, members := range queues {
+		key := msg.Subject + " " + queue
+		targets = append(targets, members[b.next[key]%len(members)])
+		b.next[key]++
+	}
+	b.mu.Unlock()
+	for _, sub := range targets {
+		go sub.handler(context.Background(), copyMessage(msg))
+	}
+	return nil
+}
+
+func copyMessage(msg *Message) *Message {
+	m := &Message{Subject: msg.Subject, Reply: msg.Reply, Data: append([]byte(nil), msg.Data...)}
+	for k, v := range msg.Header {
+		m.SetHeader(k, v)
+	}
+	return m
+}
@@ -0,0 +1,426 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"strings"
+	"sync"
+
+	"github.com/wlMalk/goms/goms/correlation"
+	"github.com/wlMalk/goms/goms/errors"
+	"github.com/wlMalk/goms/goms/log/contextual"
+	"github.com/wlMalk/goms/goms/request"
+	"github.com/wlMalk/goms/goms/service"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/rs/xid"
+)
+
+const (
+	HeaderMessageID       = "X-Message-ID"
+	HeaderInReplyTo       = "X-In-Reply-To"
+	HeaderErrorKind       = "X-Error-Kind"
+	HeaderCorrelationID   = "X-Correlation-ID"
+	HeaderCallerRequestID = "X-Caller-Request-ID"
+)
+
+type Message struct {
+	Subject string
+	Reply   string
+	Header  map[string]string
+	Data    []byte
+}
+
+func (m *Message) SetHeader(key string, value string) {
+	if m.Header == nil {
+		m.Header = map[string]string{}
+	}
+	m.Header[key] = value
+}
+
+type Handler func(ctx context.Context, msg *Message)
+
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Broker delivers every message published on a subject to all of its plain
+// subscribers, and to a single member of each of its queue groups.
+type Broker interface {
+	Publish(ctx context.Context, msg *Message) error
+	Subscribe(subject string, queue string, handler Handler) (Subscription, error)
+}
+
+type DecodeRequestFunc func(ctx context.Context, msg *Message) (request interface{}, err error)
+
+type EncodeResponseFunc func(ctx context.Context, msg *Message, response interface{}) error
+
+type EncodeRequestFunc func(ctx context.Context, msg *Message, request interface{}) error
+
+type DecodeResponseFunc func(ctx context.Context, msg *Message) (response interface{}, err error)
+
+type RequestFunc func(ctx context.Context, msg *Message) context.Context
+
+type ErrorBody struct {
+	Message    string                  `json:"message"`
+	Violations []errors.FieldViolation `json:"violations,omitempty"`
+}
+
+func EncodeError(err error, msg *Message) {
+	body := &ErrorBody{Message: err.Error()}
+	var verr *errors.ErrValidation
+	if goerrors.As(err, &verr) {
+		body.Violations = verr.Violations
+	}
+	msg.SetHeader(HeaderErrorKind, errors.KindOf(err).String())
+	msg.Data, _ = json.Marshal(body)
+}
+
+func DecodeError(msg *Message) error {
+	body := &ErrorBody{}
+	if json.Unmarshal(msg.Data, body) != nil {
+		body.Message = string(msg.Data)
+	}
+	if len(body.Violations) > 0 {
+		return &errors.ErrValidation{Violations: body.Violations}
+	}
+	kind := errors.ParseKind(msg.Header[HeaderErrorKind])
+	if kind == errors.KindUnknown {
+		return goerrors.New(body.Message)
+	}
+	return errors.New(kind, body.Message)
+}
+
+func ErrorDecoder(dec DecodeResponseFunc) DecodeResponseFunc {
+	return func(ctx context.Context, msg *Message) (interface{}, error) {
+		if _, ok := msg.Header[HeaderErrorKind]; ok {
+			return nil, DecodeError(msg)
+		}
+		return dec(ctx, msg)
+	}
+}
+
+var ErrServerClosed = goerrors.New("mq: server closed")
+
+type method struct {
+	e      endpoint.Endpoint
+	dec    DecodeRequestFunc
+	enc    EncodeResponseFunc
+	before []RequestFunc
+	after  []RequestFunc
+}
+
+type ServerOption func(*method)
+
+func ServerBefore(before ...RequestFunc) ServerOption {
+	return func(m *method) { m.before = append(m.before, before...) }
+}
+
+func ServerAfter(after ...RequestFunc) ServerOption {
+	return func(m *method) { m.after = append(m.after, after...) }
+}
+
+// Server subscribes in a queue group so that every message is handled by a
+// single instance of the service.
+type Server struct {
+	broker  Broker
+	queue   string
+	methods map[string]*method
+	mu      sync.Mutex
+	subs    []Subscription
+	done    chan struct{}
+	closed  bool
+}
+
+func NewServer(broker Broker, queue string) *Server {
+	return &Server{broker: broker, queue: queue, methods: map[string]*method{}, done: make(chan struct{})}
+}
+
+func (s *Server) RegisterMethod(subject string, e endpoint.Endpoint, dec DecodeRequestFunc, enc EncodeResponseFunc, opts ...ServerOption) {
+	m := &method{e: e, dec: dec, enc: enc}
+	for _, opt := range opts {
+		opt(m)
+	}
+	s.methods[subject] = m
+}
+
+func (s *Server) Serve() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	for subject, m := range s.methods {
+		sub, err := s.broker.Subscribe(subject, s.queue, s.handler(m))
+		if err != nil {
+			s.mu.Unlock()
+			s.Close()
+			return err
+		}
+		s.subs = append(s.subs, sub)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return ErrServerClosed
+}
+
+func (s *Server) Close() (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for _, sub := range s.subs {
+		if uerr := sub.Unsubscribe(); uerr != nil && err == nil {
+			err = uerr
+		}
+	}
+	close(s.done)
+	return err
+}
+
+func (s *Server) handler(m *method) Handler {
+	return func(ctx context.Context, msg *Message) {
+		for _, f := range m.before {
+			ctx = f(ctx, msg)
+		}
+		reply := &Message{Subject: msg.Reply}
+		if id, ok := msg.Header[HeaderMessageID]; ok {
+			reply.SetHeader(HeaderInReplyTo, id)
+		}
+		request, err := m.dec(ctx, msg)
+		if err == nil {
+			var response interface{}
+			response, err = m.e(ctx, request)
+			if err == nil {
+				err = m.enc(ctx, reply, response)
+			}
+		}
+		if err != nil {
+			reply.Data = nil
+			EncodeError(err, reply)
+		}
+		if msg.Reply == "" {
+			return
+		}
+		for _, f := range m.after {
+			ctx = f(ctx, reply)
+		}
+		s.broker.Publish(ctx, reply)
+	}
+}
+
+// Inbox matches the replies to requests by their message IDs.
+type Inbox struct {
+	broker  Broker
+	subject string
+	mu      sync.Mutex
+	sub     Subscription
+	pending map[string]chan *Message
+}
+
+func NewInbox(broker Broker) *Inbox {
+	return &Inbox{broker: broker, subject: "_INBOX." + xid.New().String(), pending: map[string]chan *Message{}}
+}
+
+func (i *Inbox) subscribe() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.sub != nil {
+		return nil
+	}
+	sub, err := i.broker.Subscribe(i.subject, "", func(ctx context.Context, msg *Message) {
+		i.mu.Lock()
+		ch, ok := i.pending[msg.Header[HeaderInReplyTo]]
+		i.mu.Unlock()
+		if ok {
+			select {
+			case ch <- msg:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	i.sub = sub
+	return nil
+}
+
+func (i *Inbox) Request(ctx context.Context, msg *Message) (*Message, error) {
+	if err := i.subscribe(); err != nil {
+		return nil, err
+	}
+	id := xid.New().String()
+	msg.Reply = i.subject
+	msg.SetHeader(HeaderMessageID, id)
+	ch := make(chan *Message, 1)
+	i.mu.Lock()
+	i.pending[id] = ch
+	i.mu.Unlock()
+	defer func() {
+		i.mu.Lock()
+		delete(i.pending, id)
+		i.mu.Unlock()
+	}()
+	if err := i.broker.Publish(ctx, msg); err != nil {
+		return nil, err
+	}
+	select {
+	case reply := <-ch:
+		return reply, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (i *Inbox) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.sub == nil {
+		return nil
+	}
+	err := i.sub.Unsubscribe()
+	i.sub = nil
+	return err
+}
+
+type Client struct {
+	inbox   *Inbox
+	subject string
+	enc     EncodeRequestFunc
+	dec     DecodeResponseFunc
+	before  []RequestFunc
+	after   []RequestFunc
+	oneWay  bool
+}
+
+type ClientOption func(*Client)
+
+func ClientBefore(before ...RequestFunc) ClientOption {
+	return func(c *Client) { c.before = append(c.before, before...) }
+}
+
+func ClientAfter(after ...RequestFunc) ClientOption {
+	return func(c *Client) { c.after = append(c.after, after...) }
+}
+
+// OneWay endpoints return a nil response without waiting for a reply.
+func OneWay() ClientOption {
+	return func(c *Client) { c.oneWay = true }
+}
+
+func NewClient(inbox *Inbox, subject string, enc EncodeRequestFunc, dec DecodeResponseFunc, opts ...ClientOption) *Client {
+	c := &Client{inbox: inbox, subject: subject, enc: enc, dec: dec}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) Endpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		msg := &Message{Subject: c.subject}
+		if err := c.enc(ctx, msg, request); err != nil {
+			return nil, err
+		}
+		for _, f := range c.before {
+			ctx = f(ctx, msg)
+		}
+		if c.oneWay {
+			return nil, c.inbox.broker.Publish(ctx, msg)
+		}
+		reply, err := c.inbox.Request(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range c.after {
+			ctx = f(ctx, reply)
+		}
+		return c.dec(ctx, reply)
+	}
+}
+
+func EncodeJSON(_ context.Context, msg *Message, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	msg.Data = data
+	return nil
+}
+
+func DecodeJSON(msg *Message, v interface{}) error {
+	if len(msg.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(msg.Data, v)
+}
+
+func LoggerInjector(logger log.Logger) RequestFunc {
+	return func(ctx context.Context, msg *Message) context.Context {
+		requestID := request.GetRequestID(ctx)
+		l := contextual.ContextualLogger(ctx, logger, "request-id", requestID)
+		return contextual.SetLogger(ctx, l)
+	}
+}
+
+func RequestIDCreator() RequestFunc {
+	return func(ctx context.Context, msg *Message) context.Context {
+		requestID := request.NewRequestID()
+		return request.SetRequestID(ctx, requestID)
+	}
+}
+
+func CorrelationIDExtractor() RequestFunc {
+	return func(ctx context.Context, msg *Message) context.Context {
+		correlationID := msg.Header[HeaderCorrelationID]
+		if len(strings.TrimSpace(correlationID)) == 0 {
+			correlationID = correlation.NewCorrelationID()
+		}
+		return correlation.SetCorrelationID(ctx, correlationID)
+	}
+}
+
+func RequestIDExtractor() RequestFunc {
+	return func(ctx context.Context, msg *Message) context.Context {
+		requestID := msg.Header[HeaderCallerRequestID]
+		if len(strings.TrimSpace(requestID)) == 0 {
+			return ctx
+		}
+		return request.SetCallerRequestID(ctx, requestID)
+	}
+}
+
+func CorrelationIDInjector() RequestFunc {
+	return func(ctx context.Context, msg *Message) context.Context {
+		correlationID := correlation.GetCorrelationID(ctx)
+		if len(strings.TrimSpace(correlationID)) > 0 {
+			msg.SetHeader(HeaderCorrelationID, correlationID)
+		}
+		return ctx
+	}
+}
+
+func RequestIDInjector() RequestFunc {
+	return func(ctx context.Context, msg *Message) context.Context {
+		requestID := request.GetRequestID(ctx)
+		if len(strings.TrimSpace(requestID)) > 0 {
+			msg.SetHeader(HeaderCallerRequestID, requestID)
+		}
+		return ctx
+	}
+}
+
+func MethodInjector(ser string, met string) RequestFunc {
+	return func(ctx context.Context, msg *Message) context.Context {
+		method := service.NewMethod(ser, met)
+		return service.SetMethod(ctx, method)
+	}
+}
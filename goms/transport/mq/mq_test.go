@@ -0,0 +1,240 @@
+package mq
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wlMalk/goms/goms/correlation"
+	"github.com/wlMalk/goms/goms/errors"
+)
+
+type testRequest struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type testResponse struct {
+	Sum int `json:"sum"`
+}
+
+func testDecodeRequest(_ context.Context, msg *Message) (interface{}, error) {
+	req := &testRequest{}
+	return req, DecodeJSON(msg, req)
+}
+
+func testDecodeResponse(_ context.Context, msg *Message) (interface{}, error) {
+	res := &testResponse{}
+	return res, DecodeJSON(msg, res)
+}
+
+func testServe(t *testing.T, broker Broker, queue string, subject string, e func(ctx context.Context, req *testRequest) (interface{}, error)) *Server {
+	t.Helper()
+	s := NewServer(broker, queue)
+	s.RegisterMethod(subject, func(ctx context.Context, request interface{}) (interface{}, error) {
+		return e(ctx, request.(*testRequest))
+	}, testDecodeRequest, EncodeJSON, ServerBefore(CorrelationIDExtractor()))
+	n := testSubscribers(broker, subject)
+	go s.Serve()
+	t.Cleanup(func() { s.Close() })
+	testWaitSubscribed(t, broker, subject, n+1)
+	return s
+}
+
+func testSubscribers(broker Broker, subject string) int {
+	b, ok := broker.(*MemoryBroker)
+	if !ok {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[subject])
+}
+
+func testWaitSubscribed(t *testing.T, broker Broker, subject string, n int) {
+	t.Helper()
+	if _, ok := broker.(*MemoryBroker); !ok {
+		return
+	}
+	for i := 0; i < 1000; i++ {
+		if testSubscribers(broker, subject) >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("'%s' was not subscribed", subject)
+}
+
+func testClient(inbox *Inbox, subject string, opts ...ClientOption) func(ctx context.Context, req *testRequest) (*testResponse, error) {
+	e := NewClient(inbox, subject, EncodeJSON, ErrorDecoder(testDecodeResponse), opts...).Endpoint()
+	return func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		res, err := e(ctx, req)
+		if err != nil || res == nil {
+			return nil, err
+		}
+		return res.(*testResponse), nil
+	}
+}
+
+func testTimeout(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestRequestReply(t *testing.T) {
+	broker := NewMemoryBroker()
+	testServe(t, broker, "svc", "svc.add", func(ctx context.Context, req *testRequest) (interface{}, error) {
+		if correlation.GetCorrelationID(ctx) != "corr" {
+			return nil, errors.Internal("missing correlation id")
+		}
+		return &testResponse{Sum: req.A + req.B}, nil
+	})
+	inbox := NewInbox(broker)
+	defer inbox.Close()
+	add := testClient(inbox, "svc.add", ClientBefore(CorrelationIDInjector()))
+	ctx := correlation.SetCorrelationID(testTimeout(t), "corr")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := add(ctx, &testRequest{A: i, B: 1})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Sum != i+1 {
+				t.Errorf("got %d, want %d", res.Sum, i+1)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestErrorReply(t *testing.T) {
+	broker := NewMemoryBroker()
+	testServe(t, broker, "svc", "svc.fail", func(ctx context.Context, req *testRequest) (interface{}, error) {
+		if req.A == 0 {
+			return nil, errors.Validation("a", "required", "a is required")
+		}
+		return nil, errors.NotFound("no such thing")
+	})
+	inbox := NewInbox(broker)
+	defer inbox.Close()
+	fail := testClient(inbox, "svc.fail")
+	_, err := fail(testTimeout(t), &testRequest{A: 1})
+	if errors.KindOf(err) != errors.KindNotFound || err.Error() != "no such thing" {
+		t.Errorf("got %v, want a not found error", err)
+	}
+	_, err = fail(testTimeout(t), &testRequest{})
+	verr, ok := err.(*errors.ErrValidation)
+	if !ok || len(verr.Violations) != 1 || verr.Violations[0].Field != "a" {
+		t.Errorf("got %v, want a validation error", err)
+	}
+}
+
+func TestQueueGroup(t *testing.T) {
+	broker := NewMemoryBroker()
+	var mu sync.Mutex
+	counts := map[string]int{}
+	for _, name := range []string{"a", "b"} {
+		name := name
+		testServe(t, broker, "svc", "svc.count", func(ctx context.Context, req *testRequest) (interface{}, error) {
+			mu.Lock()
+			counts[name]++
+			mu.Unlock()
+			return nil, nil
+		})
+	}
+	inbox := NewInbox(broker)
+	defer inbox.Close()
+	count := testClient(inbox, "svc.count")
+	for i := 0; i < 10; i++ {
+		if _, err := count(testTimeout(t), &testRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if counts["a"] != 5 || counts["b"] != 5 {
+		t.Errorf("expected the requests to be shared by the group, got %v", counts)
+	}
+}
+
+func TestOneWayAndTimeout(t *testing.T) {
+	broker := NewMemoryBroker()
+	called := make(chan *testRequest, 1)
+	testServe(t, broker, "svc", "svc.notify", func(ctx context.Context, req *testRequest) (interface{}, error) {
+		called <- req
+		return nil, nil
+	})
+	inbox := NewInbox(broker)
+	defer inbox.Close()
+	res, err := testClient(inbox, "svc.notify", OneWay())(testTimeout(t), &testRequest{A: 7})
+	if err != nil || res != nil {
+		t.Fatalf("got %v, %v", res, err)
+	}
+	select {
+	case req := <-called:
+		if req.A != 7 {
+			t.Errorf("got %d, want 7", req.A)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the one way request was not handled")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := testClient(inbox, "svc.missing")(ctx, &testRequest{}); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	broker := NewMemoryBroker()
+	s := NewServer(broker, "svc")
+	s.RegisterMethod("svc.add", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, nil
+	}, testDecodeRequest, EncodeJSON)
+	served := make(chan error)
+	go func() { served <- s.Serve() }()
+	testWaitSubscribed(t, broker, "svc.add", 1)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-served; err != ErrServerClosed {
+		t.Errorf("got %v, want %v", err, ErrServerClosed)
+	}
+	if n := len(broker.subs["svc.add"]); n != 0 {
+		t.Errorf("expected no subscriptions after close, got %d", n)
+	}
+	if err := s.Serve(); err != ErrServerClosed {
+		t.Errorf("got %v, want %v", err, ErrServerClosed)
+	}
+}
+
+func TestMemoryBroker(t *testing.T) {
+	broker := NewMemoryBroker()
+	got := make(chan *Message, 3)
+	handler := func(ctx context.Context, msg *Message) { got <- msg }
+	sub, _ := broker.Subscribe("s", "", handler)
+	broker.Subscribe("s", "", handler)
+	broker.Subscribe("other", "", handler)
+	msg := &Message{Subject: "s", Data: []byte("x")}
+	msg.SetHeader("K", "v")
+	broker.Publish(context.Background(), msg)
+	for i := 0; i < 2; i++ {
+		m := <-got
+		if m == msg || string(m.Data) != "x" || m.Header["K"] != "v" {
+			t.Errorf("expected a copy of the message, got %v", m)
+		}
+	}
+	sub.Unsubscribe()
+	broker.Publish(context.Background(), msg)
+	<-got
+	select {
+	case m := <-got:
+		t.Errorf("unexpected message %v", m)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,43 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/wlMalk/goms/goms/transport/mq"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Broker is a mq.Broker over a NATS connection. Message headers need a
+// server supporting them, from version 2.2.
+type Broker struct {
+	conn *nats.Conn
+}
+
+func NewBroker(conn *nats.Conn) *Broker {
+	return &Broker{conn: conn}
+}
+
+func (b *Broker) Publish(_ context.Context, msg *mq.Message) error {
+	m := nats.NewMsg(msg.Subject)
+	m.Reply = msg.Reply
+	m.Data = msg.Data
+	for key, value := range msg.Header {
+		m.Header.Set(key, value)
+	}
+	return b.conn.PublishMsg(m)
+}
+
+func (b *Broker) Subscribe(subject string, queue string, handler mq.Handler) (mq.Subscription, error) {
+	cb := func(m *nats.Msg) {
+		msg := &mq.Message{Subject: m.Subject, Reply: m.Reply, Data: m.Data}
+		for key := range m.Header {
+			msg.SetHeader(key, m.Header.Get(key))
+		}
+		handler(context.Background(), msg)
+	}
+	if queue == "" {
+		return b.conn.Subscribe(subject, cb)
+	}
+	return b.conn.QueueSubscribe(subject, queue, cb)
+}
@@ -0,0 +1,84 @@
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wlMalk/goms/goms/errors"
+	"github.com/wlMalk/goms/goms/transport/mq"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+type testRequest struct {
+	Name string `json:"name"`
+}
+
+type testResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func TestRequestReply(t *testing.T) {
+	server := natsserver.RunRandClientPortServer()
+	defer server.Shutdown()
+	conn, err := nats.Connect(server.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	broker := NewBroker(conn)
+
+	s := mq.NewServer(broker, "greeter")
+	s.RegisterMethod("greeter.greet", func(ctx context.Context, request interface{}) (interface{}, error) {
+		name := request.(*testRequest).Name
+		if name == "" {
+			return nil, errors.Validation("name", "required", "name is required")
+		}
+		if name == "nobody" {
+			return nil, errors.NotFound("no one to greet")
+		}
+		return &testResponse{Greeting: "hello " + name}, nil
+	}, func(_ context.Context, msg *mq.Message) (interface{}, error) {
+		req := &testRequest{}
+		return req, mq.DecodeJSON(msg, req)
+	}, mq.EncodeJSON)
+	go s.Serve()
+	defer s.Close()
+
+	inbox := mq.NewInbox(broker)
+	defer inbox.Close()
+	greet := mq.NewClient(inbox, "greeter.greet", mq.EncodeJSON, mq.ErrorDecoder(func(_ context.Context, msg *mq.Message) (interface{}, error) {
+		res := &testResponse{}
+		return res, mq.DecodeJSON(msg, res)
+	})).Endpoint()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// the server subscribes in the background, so the first requests may be lost
+	var res interface{}
+	for ctx.Err() == nil {
+		attemptCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		res, err = greet(attemptCtx, &testRequest{Name: "world"})
+		cancel()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.(*testResponse).Greeting; got != "hello world" {
+		t.Errorf("got %q, want %q", got, "hello world")
+	}
+
+	_, err = greet(ctx, &testRequest{Name: "nobody"})
+	if errors.KindOf(err) != errors.KindNotFound || err.Error() != "no one to greet" {
+		t.Errorf("got %v, want a not found error", err)
+	}
+	_, err = greet(ctx, &testRequest{})
+	if verr, ok := err.(*errors.ErrValidation); !ok || len(verr.Violations) != 1 || verr.Violations[0].Field != "name" {
+		t.Errorf("got %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	strs "strings"
+
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func ImportFile(path string, serviceName string) (*types.Service, error) {
+	switch strs.ToLower(filepath.Ext(path)) {
+	case ".proto":
+		return ImportProto(path, serviceName)
+	case ".yaml", ".yml", ".json":
+		return ImportOpenAPI(path)
+	default:
+		return nil, fmt.Errorf("cannot import '%s': unknown file type, expected .proto, .yaml, .yml or .json", path)
+	}
+}
+
+var versionRegexp = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+func parseVersion(s string) (v types.Version) {
+	m := versionRegexp.FindStringSubmatch(strs.TrimSpace(s))
+	if m == nil {
+		v.Major = 1
+		return
+	}
+	fmt.Sscan(m[1], &v.Major)
+	if m[2] != "" {
+		fmt.Sscan(m[2], &v.Minor)
+	}
+	if m[3] != "" {
+		fmt.Sscan(m[3], &v.Patch)
+	}
+	return
+}
+
+var identRegexp = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+func packageName(s string) string {
+	name := strs.ToLower(strings.ToSnakeCase(strs.Trim(identRegexp.ReplaceAllString(s, "_"), "_")))
+	if name == "" {
+		return "service"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "service_" + name
+	}
+	return name
+}
+
+func typeName(s string) string {
+	name := strings.ToCamelCase(strs.Trim(identRegexp.ReplaceAllString(s, "_"), "_"))
+	if name == "" {
+		return "Type"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "T" + name
+	}
+	return name
+}
+
+func argumentName(s string) string {
+	name := strings.ToLowerFirst(typeName(s))
+	if token.Lookup(name).IsKeyword() || name == "ctx" || name == "err" {
+		name += "Value"
+	}
+	return name
+}
+
+func newArgument(name string, t *types.Type, docs []string, origin string) *types.Argument {
+	arg := &types.Argument{Name: argumentName(name), Type: t, Docs: docs}
+	if arg.Name != name {
+		arg.Alias = name
+	}
+	arg.Options.HTTP.Origin = origin
+	return arg
+}
+
+func newResult(name string, t *types.Type, docs []string) *types.Field {
+	field := &types.Field{Name: argumentName(name), Type: t, Docs: docs}
+	if field.Name != name {
+		field.Alias = name
+	}
+	return field
+}
+
+func renameResults(method *types.Method) {
+	names := map[string]bool{}
+	for _, arg := range method.Arguments {
+		names[arg.Name] = true
+	}
+	for _, result := range method.Results {
+		if names[result.Name] {
+			if result.Alias == "" {
+				result.Alias = result.Name
+			}
+			result.Name += "Result"
+		}
+	}
+}
+
+func newField(name string, t *types.Type, docs []string) *types.Field {
+	field := &types.Field{Name: strings.ToUpperFirst(typeName(name)), Type: t, Docs: docs}
+	if field.Name != name && strings.ToLowerFirst(field.Name) != name {
+		field.Alias = name
+	}
+	return field
+}
+
+func builtinType(name string) *types.Type {
+	if name == "[]byte" {
+		return &types.Type{Name: "byte", IsBytes: true, IsBuiltin: true}
+	}
+	return &types.Type{Name: name, IsBuiltin: true}
+}
+
+func timeType() *types.Type {
+	return &types.Type{Name: "Time", Pkg: "time", PkgImportPath: "time", IsImport: true}
+}
+
+func goURI(uri string) string {
+	segments := strs.Split(uri, "/")
+	for i, segment := range segments {
+		if strs.HasPrefix(segment, "{") && strs.HasSuffix(segment, "}") {
+			name := strs.TrimSuffix(strs.TrimPrefix(segment, "{"), "}")
+			if j := strs.Index(name, "="); j >= 0 {
+				name = name[:j]
+			}
+			segments[i] = ":" + strings.ToSnakeCase(argumentName(name))
+		}
+	}
+	return strs.Join(segments, "/")
+}
+
+var uriVersionRegexp = regexp.MustCompile(`^/v\d+(\.\d+)*(/|$)`)
+
+func absURI(uri string) string {
+	return "/" + strs.TrimPrefix(uriVersionRegexp.ReplaceAllString(uri, "/"), "/")
+}
+
+func pathParams(uri string) (params []string) {
+	for _, segment := range strs.Split(uri, "/") {
+		if strs.HasPrefix(segment, "{") && strs.HasSuffix(segment, "}") {
+			name := strs.TrimSuffix(strs.TrimPrefix(segment, "{"), "}")
+			if j := strs.Index(name, "="); j >= 0 {
+				name = name[:j]
+			}
+			params = append(params, name)
+		}
+	}
+	return
+}
+
+func prune(service *types.Service) {
+	entities := map[string]*types.Entity{}
+	for i := range service.Entities {
+		entities[service.Entities[i].Name] = &service.Entities[i]
+	}
+	used := map[string]bool{}
+	var visit func(t *types.Type)
+	visit = func(t *types.Type) {
+		if t == nil || used[t.Name] {
+			return
+		}
+		if t.IsMap {
+			visit(t.Value)
+		}
+		if t.IsEnum {
+			used[t.Name] = true
+		}
+		if e, ok := entities[t.Name]; ok && t.IsEntity {
+			used[t.Name] = true
+			for _, field := range e.Fields {
+				visit(field.Type)
+			}
+		}
+	}
+	for _, method := range service.Methods {
+		for _, arg := range method.Arguments {
+			visit(arg.Type)
+		}
+		for _, result := range method.Results {
+			visit(result.Type)
+		}
+	}
+	var es []types.Entity
+	for _, e := range service.Entities {
+		if used[e.Name] {
+			es = append(es, e)
+		}
+	}
+	var ens []types.Enum
+	for _, e := range service.Enums {
+		if used[e.Name] {
+			ens = append(ens, e)
+		}
+	}
+	service.Entities, service.Enums = es, ens
+}
@@ -0,0 +1,400 @@
+package importer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	strs "strings"
+
+	"github.com/wlMalk/goms/parser/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+type openAPIDocument struct {
+	Info struct {
+		Title       string `yaml:"title"`
+		Description string `yaml:"description"`
+		Version     string `yaml:"version"`
+	} `yaml:"info"`
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Components struct {
+		Schemas       map[string]*openAPISchema    `yaml:"schemas"`
+		Parameters    map[string]*openAPIParameter `yaml:"parameters"`
+		RequestBodies map[string]*openAPIBody      `yaml:"requestBodies"`
+		Responses     map[string]*openAPIBody      `yaml:"responses"`
+	} `yaml:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string                  `yaml:"operationId"`
+	Summary     string                  `yaml:"summary"`
+	Description string                  `yaml:"description"`
+	Parameters  []*openAPIParameter     `yaml:"parameters"`
+	RequestBody *openAPIBody            `yaml:"requestBody"`
+	Responses   map[string]*openAPIBody `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPIBody struct {
+	Ref     string `yaml:"$ref"`
+	Content map[string]struct {
+		Schema *openAPISchema `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+type openAPISchema struct {
+	Ref                  string           `yaml:"$ref"`
+	Type                 string           `yaml:"type"`
+	Format               string           `yaml:"format"`
+	Description          string           `yaml:"description"`
+	Items                *openAPISchema   `yaml:"items"`
+	Properties           yaml.Node        `yaml:"properties"`
+	AdditionalProperties *openAPISchema   `yaml:"additionalProperties"`
+	Enum                 []yaml.Node      `yaml:"enum"`
+	EnumNames            []string         `yaml:"x-enum-varnames"`
+	AllOf                []*openAPISchema `yaml:"allOf"`
+}
+
+func (s *openAPISchema) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!bool" {
+		return nil
+	}
+	type schema openAPISchema
+	return value.Decode((*schema)(s))
+}
+
+type openAPIProperty struct {
+	Name   string
+	Prefix string
+	Schema *openAPISchema
+}
+
+func (imp *openAPIImporter) properties(s *openAPISchema, prefix string) (properties []openAPIProperty, err error) {
+	if s.Ref != "" {
+		ref, ok := imp.doc.Components.Schemas[openAPIRefName(s.Ref)]
+		if !ok {
+			return nil, fmt.Errorf("schema '%s' was not found", s.Ref)
+		}
+		return imp.properties(ref, typeName(openAPIRefName(s.Ref)))
+	}
+	for _, sub := range s.AllOf {
+		ps, err := imp.properties(sub, prefix)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, ps...)
+	}
+	for i := 0; i+1 < len(s.Properties.Content); i += 2 {
+		property := &openAPISchema{}
+		if err := s.Properties.Content[i+1].Decode(property); err != nil {
+			return nil, err
+		}
+		properties = append(properties, openAPIProperty{Name: s.Properties.Content[i].Value, Prefix: prefix, Schema: property})
+	}
+	return
+}
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "patch", "head", "options"}
+
+type openAPIImporter struct {
+	doc     *openAPIDocument
+	service *types.Service
+	done    map[string]bool
+}
+
+func ImportOpenAPI(path string) (*types.Service, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := &openAPIDocument{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("cannot parse '%s': %v", path, err)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, fmt.Errorf("no paths were found in '%s'", path)
+	}
+	imp := &openAPIImporter{
+		doc:     doc,
+		service: &types.Service{},
+		done:    map[string]bool{},
+	}
+	imp.service.Name = packageName(doc.Info.Title)
+	imp.service.Version = parseVersion(doc.Info.Version)
+	if doc.Info.Description != "" {
+		imp.service.Docs = []string{doc.Info.Description}
+	}
+
+	var paths []string
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		var common []*openAPIParameter
+		if node, ok := item["parameters"]; ok {
+			if err := node.Decode(&common); err != nil {
+				return nil, err
+			}
+		}
+		for _, httpMethod := range openAPIMethods {
+			node, ok := item[httpMethod]
+			if !ok {
+				continue
+			}
+			op := &openAPIOperation{}
+			if err := node.Decode(op); err != nil {
+				return nil, fmt.Errorf("cannot parse '%s %s': %v", strs.ToUpper(httpMethod), path, err)
+			}
+			method, err := imp.method(path, strs.ToUpper(httpMethod), op, common)
+			if err != nil {
+				return nil, fmt.Errorf("'%s %s': %v", strs.ToUpper(httpMethod), path, err)
+			}
+			imp.service.Methods = append(imp.service.Methods, method)
+		}
+	}
+	prune(imp.service)
+	return imp.service, nil
+}
+
+func (imp *openAPIImporter) method(path string, httpMethod string, op *openAPIOperation, common []*openAPIParameter) (method types.Method, err error) {
+	name := op.OperationID
+	if name == "" {
+		name = strs.ToLower(httpMethod) + "_" + strs.Replace(strs.Trim(absURI(path), "/"), "/", "_", -1)
+	}
+	method.Name = typeName(name)
+	for _, doc := range []string{op.Summary, op.Description} {
+		if doc != "" {
+			method.Docs = append(method.Docs, doc)
+		}
+	}
+	method.Options.HTTP.Method = httpMethod
+	method.Options.HTTP.AbsURI = goURI(absURI(path))
+
+	for _, param := range append(common, op.Parameters...) {
+		if param.Ref != "" {
+			ref, ok := imp.doc.Components.Parameters[openAPIRefName(param.Ref)]
+			if !ok {
+				return method, fmt.Errorf("parameter '%s' was not found", param.Ref)
+			}
+			param = ref
+		}
+		var origin string
+		switch param.In {
+		case "path":
+			origin = "PATH"
+		case "query":
+			origin = "QUERY"
+		case "header":
+			origin = "HEADER"
+		default:
+			continue
+		}
+		t, err := imp.schemaType(method.Name+typeName(param.Name), param.Schema)
+		if err != nil {
+			return method, err
+		}
+		method.Arguments = append(method.Arguments, newArgument(param.Name, t, openAPIDocs(param.Description), origin))
+	}
+
+	if op.RequestBody != nil {
+		body := op.RequestBody
+		if body.Ref != "" {
+			if body = imp.doc.Components.RequestBodies[openAPIRefName(body.Ref)]; body == nil {
+				return method, fmt.Errorf("request body '%s' was not found", op.RequestBody.Ref)
+			}
+		}
+		origin := "BODY"
+		if httpMethod != "POST" && httpMethod != "PUT" && httpMethod != "PATCH" {
+			origin = "QUERY"
+		}
+		properties, err := imp.bodyProperties(method.Name+"Request", "body", body)
+		if err != nil {
+			return method, err
+		}
+		for _, p := range properties {
+			t, err := imp.schemaType(p.Prefix+typeName(p.Name), p.Schema)
+			if err != nil {
+				return method, err
+			}
+			method.Arguments = append(method.Arguments, newArgument(p.Name, t, openAPIDocs(p.Schema.Description), origin))
+		}
+	}
+
+	var codes []string
+	for code := range op.Responses {
+		if strs.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	if len(codes) > 0 {
+		response := op.Responses[codes[0]]
+		if response.Ref != "" {
+			if response = imp.doc.Components.Responses[openAPIRefName(response.Ref)]; response == nil {
+				return method, fmt.Errorf("response '%s' was not found", op.Responses[codes[0]].Ref)
+			}
+		}
+		properties, err := imp.bodyProperties(method.Name+"Response", "result", response)
+		if err != nil {
+			return method, err
+		}
+		for _, p := range properties {
+			t, err := imp.schemaType(p.Prefix+typeName(p.Name), p.Schema)
+			if err != nil {
+				return method, err
+			}
+			method.Results = append(method.Results, newResult(p.Name, t, openAPIDocs(p.Schema.Description)))
+		}
+		renameResults(&method)
+	}
+	return
+}
+
+func (imp *openAPIImporter) bodyProperties(prefix string, fallback string, body *openAPIBody) ([]openAPIProperty, error) {
+	content, ok := body.Content["application/json"]
+	if !ok || content.Schema == nil {
+		return nil, nil
+	}
+	schema := content.Schema
+	if schema.Ref != "" {
+		ref, ok := imp.doc.Components.Schemas[openAPIRefName(schema.Ref)]
+		if !ok {
+			return nil, fmt.Errorf("schema '%s' was not found", schema.Ref)
+		}
+		if ref.Type != "object" && len(ref.Properties.Content) == 0 && len(ref.AllOf) == 0 {
+			return []openAPIProperty{{Name: fallback, Prefix: prefix, Schema: schema}}, nil
+		}
+	}
+	if schema.Ref == "" && schema.Type != "object" && len(schema.Properties.Content) == 0 && len(schema.AllOf) == 0 {
+		return []openAPIProperty{{Name: fallback, Prefix: prefix, Schema: schema}}, nil
+	}
+	return imp.properties(schema, prefix)
+}
+
+func (imp *openAPIImporter) schemaType(name string, schema *openAPISchema) (*types.Type, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("missing schema for '%s'", name)
+	}
+	if schema.Ref != "" {
+		refName := openAPIRefName(schema.Ref)
+		ref, ok := imp.doc.Components.Schemas[refName]
+		if !ok {
+			return nil, fmt.Errorf("schema '%s' was not found", schema.Ref)
+		}
+		return imp.namedType(typeName(refName), ref)
+	}
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			return timeType(), nil
+		case "byte", "binary":
+			return builtinType("[]byte"), nil
+		}
+		return builtinType("string"), nil
+	case "integer":
+		if len(schema.Enum) > 0 {
+			return imp.namedType(name, schema)
+		}
+		switch schema.Format {
+		case "int32":
+			return builtinType("int32"), nil
+		case "int64":
+			return builtinType("int64"), nil
+		}
+		return builtinType("int"), nil
+	case "number":
+		if schema.Format == "float" {
+			return builtinType("float32"), nil
+		}
+		return builtinType("float64"), nil
+	case "boolean":
+		return builtinType("bool"), nil
+	case "array":
+		t, err := imp.schemaType(name+"Item", schema.Items)
+		if err != nil {
+			return nil, err
+		}
+		if t.IsSlice || t.IsMap || t.IsBytes {
+			return nil, fmt.Errorf("nested arrays are not supported in '%s'", name)
+		}
+		t.IsSlice = true
+		return t, nil
+	}
+	if schema.AdditionalProperties != nil && len(schema.Properties.Content) == 0 {
+		value, err := imp.schemaType(name+"Value", schema.AdditionalProperties)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Type{Name: "string", IsMap: true, Value: value}, nil
+	}
+	if schema.Type == "object" || len(schema.Properties.Content) > 0 || len(schema.AllOf) > 0 {
+		return imp.namedType(name, schema)
+	}
+	return nil, fmt.Errorf("unsupported schema for '%s'", name)
+}
+
+func (imp *openAPIImporter) namedType(name string, schema *openAPISchema) (*types.Type, error) {
+	if schema.Type == "integer" && len(schema.Enum) > 0 {
+		if !imp.done[name] {
+			imp.done[name] = true
+			enum := types.Enum{Name: name, Docs: openAPIDocs(schema.Description)}
+			for i, node := range schema.Enum {
+				c := types.EnumCase{Name: name + node.Value}
+				if err := node.Decode(&c.Value); err != nil {
+					return nil, fmt.Errorf("invalid value '%s' for '%s' enum", node.Value, name)
+				}
+				if i < len(schema.EnumNames) {
+					c.Name = typeName(strs.ToLower(schema.EnumNames[i]))
+				}
+				enum.Cases = append(enum.Cases, c)
+			}
+			imp.service.Enums = append(imp.service.Enums, enum)
+		}
+		return &types.Type{Name: name, IsEnum: true}, nil
+	}
+	if schema.Type != "object" && len(schema.Properties.Content) == 0 && len(schema.AllOf) == 0 {
+		return imp.schemaType(name, &openAPISchema{Type: schema.Type, Format: schema.Format, Items: schema.Items, AdditionalProperties: schema.AdditionalProperties})
+	}
+	if len(schema.Properties.Content) == 0 && len(schema.AllOf) == 0 && schema.AdditionalProperties != nil {
+		return imp.schemaType(name, &openAPISchema{AdditionalProperties: schema.AdditionalProperties})
+	}
+	if !imp.done[name] {
+		imp.done[name] = true
+		properties, err := imp.properties(schema, name)
+		if err != nil {
+			return nil, err
+		}
+		entity := types.Entity{Name: name, Docs: openAPIDocs(schema.Description)}
+		for _, p := range properties {
+			t, err := imp.schemaType(p.Prefix+typeName(p.Name), p.Schema)
+			if err != nil {
+				return nil, err
+			}
+			entity.Fields = append(entity.Fields, newField(p.Name, t, openAPIDocs(p.Schema.Description)))
+		}
+		imp.service.Entities = append(imp.service.Entities, entity)
+	}
+	return &types.Type{Name: name, IsEntity: true}, nil
+}
+
+func openAPIRefName(ref string) string {
+	return ref[strs.LastIndex(ref, "/")+1:]
+}
+
+func openAPIDocs(description string) []string {
+	if description == "" {
+		return nil
+	}
+	return []string{description}
+}
@@ -0,0 +1,375 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	strs "strings"
+
+	"github.com/wlMalk/goms/parser/types"
+
+	"github.com/emicklei/proto"
+)
+
+type protoImporter struct {
+	messages map[string]*proto.Message
+	enums    map[string]*proto.Enum
+	names    map[interface{}]string
+	service  *types.Service
+	done     map[string]bool
+}
+
+func ImportProto(path string, serviceName string) (*types.Service, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p := proto.NewParser(f)
+	p.Filename(path)
+	definition, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &protoImporter{
+		messages: map[string]*proto.Message{},
+		enums:    map[string]*proto.Enum{},
+		names:    map[interface{}]string{},
+		service:  &types.Service{},
+		done:     map[string]bool{},
+	}
+	var pkg string
+	var services []*proto.Service
+	proto.Walk(definition,
+		proto.WithPackage(func(p *proto.Package) {
+			pkg = p.Name
+		}),
+		proto.WithService(func(s *proto.Service) {
+			services = append(services, s)
+		}),
+		proto.WithMessage(func(m *proto.Message) {
+			name := protoQualifiedName(m)
+			imp.messages[name] = m
+			imp.names[m] = name
+		}),
+		proto.WithEnum(func(e *proto.Enum) {
+			name := protoQualifiedName(e)
+			imp.enums[name] = e
+			imp.names[e] = name
+		}))
+
+	var s *proto.Service
+	var names []string
+	for _, service := range services {
+		names = append(names, service.Name)
+		if serviceName == "" || strs.EqualFold(service.Name, serviceName) {
+			if s != nil && serviceName == "" {
+				return nil, fmt.Errorf("'%s' declares more than one service, choose one of: %s", path, strs.Join(names, ", "))
+			}
+			s = service
+		}
+	}
+	if s == nil {
+		if serviceName != "" {
+			return nil, fmt.Errorf("service '%s' was not found in '%s'", serviceName, path)
+		}
+		return nil, fmt.Errorf("no service was found in '%s'", path)
+	}
+
+	imp.service.Name = packageName(s.Name)
+	imp.service.Version = protoPackageVersion(pkg)
+	imp.service.Docs = protoDocs(s.Comment)
+	for _, element := range s.Elements {
+		rpc, ok := element.(*proto.RPC)
+		if !ok {
+			continue
+		}
+		if rpc.StreamsRequest || rpc.StreamsReturns {
+			return nil, fmt.Errorf("streaming rpc '%s' is not supported", rpc.Name)
+		}
+		method, err := imp.method(rpc)
+		if err != nil {
+			return nil, err
+		}
+		imp.service.Methods = append(imp.service.Methods, method)
+	}
+	prune(imp.service)
+	return imp.service, nil
+}
+
+func (imp *protoImporter) method(rpc *proto.RPC) (method types.Method, err error) {
+	method.Name = typeName(rpc.Name)
+	method.Docs = protoDocs(rpc.Comment)
+	request, ok := imp.lookupMessage(rpc.RequestType)
+	if !ok && !protoIsEmpty(rpc.RequestType) {
+		return method, fmt.Errorf("request type '%s' of rpc '%s' was not found", rpc.RequestType, rpc.Name)
+	}
+	response, ok := imp.lookupMessage(rpc.ReturnsType)
+	if !ok && !protoIsEmpty(rpc.ReturnsType) {
+		return method, fmt.Errorf("response type '%s' of rpc '%s' was not found", rpc.ReturnsType, rpc.Name)
+	}
+
+	httpMethod, uri, body := protoHTTPRule(rpc)
+	params := map[string]bool{}
+	if uri != "" {
+		method.Options.HTTP.Method = httpMethod
+		method.Options.HTTP.AbsURI = goURI(absURI(uri))
+		for _, param := range pathParams(uri) {
+			params[param] = true
+		}
+	}
+
+	if request != nil {
+		for _, field := range protoFields(request) {
+			t, err := imp.fieldType(request, field)
+			if err != nil {
+				return method, err
+			}
+			origin := "BODY"
+			if params[field.Name] {
+				origin = "PATH"
+			} else if uri != "" && (body == "" || body != "*" && body != field.Name) {
+				origin = "QUERY"
+			}
+			method.Arguments = append(method.Arguments, newArgument(field.Name, t, protoDocs(field.Comment), origin))
+		}
+	}
+	if response != nil {
+		for _, field := range protoFields(response) {
+			t, err := imp.fieldType(response, field)
+			if err != nil {
+				return method, err
+			}
+			method.Results = append(method.Results, newResult(field.Name, t, protoDocs(field.Comment)))
+		}
+	}
+	renameResults(&method)
+	if uri != "" && httpMethod != "POST" && httpMethod != "PUT" && httpMethod != "PATCH" {
+		for _, arg := range method.Arguments {
+			if arg.Options.HTTP.Origin == "BODY" {
+				arg.Options.HTTP.Origin = "QUERY"
+			}
+		}
+	}
+	return
+}
+
+type protoField struct {
+	Name     string
+	Type     string
+	KeyType  string
+	Repeated bool
+	Comment  *proto.Comment
+}
+
+func protoFields(m *proto.Message) (fields []protoField) {
+	var add func(elements []proto.Visitee)
+	add = func(elements []proto.Visitee) {
+		for _, element := range elements {
+			switch f := element.(type) {
+			case *proto.NormalField:
+				fields = append(fields, protoField{Name: f.Name, Type: f.Type, Repeated: f.Repeated, Comment: f.Comment})
+			case *proto.MapField:
+				fields = append(fields, protoField{Name: f.Name, Type: f.Type, KeyType: f.KeyType, Comment: f.Comment})
+			case *proto.OneOfField:
+				fields = append(fields, protoField{Name: f.Name, Type: f.Type, Comment: f.Comment})
+			case *proto.Oneof:
+				add(f.Elements)
+			}
+		}
+	}
+	add(m.Elements)
+	return
+}
+
+func (imp *protoImporter) fieldType(scope *proto.Message, field protoField) (*types.Type, error) {
+	t, err := imp.scalarType(scope, field.Type)
+	if err != nil {
+		return nil, err
+	}
+	if field.KeyType != "" {
+		key, err := imp.scalarType(scope, field.KeyType)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Type{Name: key.Name, IsMap: true, Value: t}, nil
+	}
+	if field.Repeated {
+		if t.IsBytes {
+			return nil, fmt.Errorf("repeated bytes field '%s' is not supported", field.Name)
+		}
+		t.IsSlice = true
+	}
+	return t, nil
+}
+
+func (imp *protoImporter) scalarType(scope *proto.Message, name string) (*types.Type, error) {
+	switch name {
+	case "double":
+		return builtinType("float64"), nil
+	case "float":
+		return builtinType("float32"), nil
+	case "int32", "sint32", "sfixed32":
+		return builtinType("int32"), nil
+	case "int64", "sint64", "sfixed64":
+		return builtinType("int64"), nil
+	case "uint32", "fixed32":
+		return builtinType("uint32"), nil
+	case "uint64", "fixed64":
+		return builtinType("uint64"), nil
+	case "bool":
+		return builtinType("bool"), nil
+	case "string":
+		return builtinType("string"), nil
+	case "bytes":
+		return builtinType("[]byte"), nil
+	case "google.protobuf.Timestamp":
+		return timeType(), nil
+	}
+	if m, ok := imp.lookupMessageFrom(scope, name); ok {
+		entityName := typeName(strs.Replace(imp.names[m], ".", "_", -1))
+		if err := imp.entity(entityName, m); err != nil {
+			return nil, err
+		}
+		return &types.Type{Name: entityName, IsEntity: true}, nil
+	}
+	if e, ok := imp.lookupEnumFrom(scope, name); ok {
+		enumName := typeName(strs.Replace(imp.names[e], ".", "_", -1))
+		imp.enum(enumName, e)
+		return &types.Type{Name: enumName, IsEnum: true}, nil
+	}
+	return nil, fmt.Errorf("unsupported type '%s'", name)
+}
+
+func (imp *protoImporter) entity(name string, m *proto.Message) error {
+	if imp.done[name] {
+		return nil
+	}
+	imp.done[name] = true
+	entity := types.Entity{Name: name, Docs: protoDocs(m.Comment)}
+	for _, field := range protoFields(m) {
+		t, err := imp.fieldType(m, field)
+		if err != nil {
+			return err
+		}
+		entity.Fields = append(entity.Fields, newField(field.Name, t, protoDocs(field.Comment)))
+	}
+	imp.service.Entities = append(imp.service.Entities, entity)
+	return nil
+}
+
+func (imp *protoImporter) enum(name string, e *proto.Enum) {
+	if imp.done[name] {
+		return
+	}
+	imp.done[name] = true
+	enum := types.Enum{Name: name, Docs: protoDocs(e.Comment)}
+	for _, element := range e.Elements {
+		if f, ok := element.(*proto.EnumField); ok {
+			enum.Cases = append(enum.Cases, types.EnumCase{Name: typeName(strs.ToLower(f.Name)), Value: f.Integer})
+		}
+	}
+	imp.service.Enums = append(imp.service.Enums, enum)
+}
+
+func (imp *protoImporter) lookupMessage(name string) (*proto.Message, bool) {
+	return imp.lookupMessageFrom(nil, name)
+}
+
+func (imp *protoImporter) lookupMessageFrom(scope *proto.Message, name string) (*proto.Message, bool) {
+	for _, candidate := range imp.candidates(scope, name) {
+		if m, ok := imp.messages[candidate]; ok {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+func (imp *protoImporter) lookupEnumFrom(scope *proto.Message, name string) (*proto.Enum, bool) {
+	for _, candidate := range imp.candidates(scope, name) {
+		if e, ok := imp.enums[candidate]; ok {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+func (imp *protoImporter) candidates(scope *proto.Message, name string) (names []string) {
+	name = strs.TrimPrefix(name, ".")
+	if scope != nil {
+		prefix := imp.names[scope]
+		for prefix != "" {
+			names = append(names, prefix+"."+name)
+			if i := strs.LastIndex(prefix, "."); i >= 0 {
+				prefix = prefix[:i]
+			} else {
+				prefix = ""
+			}
+		}
+	}
+	names = append(names, name)
+	if i := strs.LastIndex(name, "."); i >= 0 {
+		names = append(names, name[i+1:])
+	}
+	return
+}
+
+func protoQualifiedName(v proto.Visitee) string {
+	var name string
+	for v != nil {
+		switch t := v.(type) {
+		case *proto.Message:
+			name = t.Name + "." + name
+			v = t.Parent
+		case *proto.Enum:
+			name = t.Name + "." + name
+			v = t.Parent
+		default:
+			v = nil
+		}
+	}
+	return strs.TrimSuffix(name, ".")
+}
+
+func protoHTTPRule(rpc *proto.RPC) (method string, uri string, body string) {
+	for _, element := range rpc.Elements {
+		option, ok := element.(*proto.Option)
+		if !ok || option.Name != "(google.api.http)" {
+			continue
+		}
+		for _, constant := range option.Constant.OrderedMap {
+			switch constant.Name {
+			case "get", "put", "post", "delete", "patch":
+				method, uri = strs.ToUpper(constant.Name), constant.Source
+			case "body":
+				body = constant.Source
+			}
+		}
+	}
+	return
+}
+
+func protoPackageVersion(pkg string) types.Version {
+	parts := strs.Split(pkg, ".")
+	last := parts[len(parts)-1]
+	if versionRegexp.MatchString(last) && strs.HasPrefix(last, "v") {
+		return parseVersion(strs.Replace(last, "_", ".", -1))
+	}
+	return types.Version{Major: 1}
+}
+
+func protoIsEmpty(name string) bool {
+	return name == "google.protobuf.Empty" || name == ".google.protobuf.Empty" || name == "Empty"
+}
+
+func protoDocs(comment *proto.Comment) (docs []string) {
+	if comment == nil {
+		return nil
+	}
+	for _, line := range comment.Lines {
+		if line = strs.TrimSpace(line); line != "" {
+			docs = append(docs, line)
+		}
+	}
+	return
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	strs "strings"
+
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func Render(service types.Service) ([]byte, error) {
+	var b strs.Builder
+	pkg := packageName(service.Name)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	imports := []string{"context"}
+	if usesTime(service) {
+		imports = append(imports, "time")
+	}
+	b.WriteString("import (\n")
+	for _, i := range imports {
+		fmt.Fprintf(&b, "\t%q\n", i)
+	}
+	b.WriteString(")\n\n")
+
+	for _, entity := range service.Entities {
+		writeDocs(&b, "", entity.Docs)
+		fmt.Fprintf(&b, "type %s struct {\n", entity.Name)
+		for _, field := range entity.Fields {
+			writeDocs(&b, "\t", field.Docs)
+			fmt.Fprintf(&b, "\t%s %s", field.Name, goType(field.Type))
+			if field.Alias != "" {
+				fmt.Fprintf(&b, " `json:\"%s\"`", field.Alias)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for _, enum := range service.Enums {
+		writeDocs(&b, "", enum.Docs)
+		fmt.Fprintf(&b, "type %s int\n\n", enum.Name)
+		b.WriteString("const (\n")
+		for _, c := range enum.Cases {
+			fmt.Fprintf(&b, "\t%s %s = %d\n", c.Name, enum.Name, c.Value)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	writeDocs(&b, "", service.Docs)
+	b.WriteString("// @generate-all\n")
+	fmt.Fprintf(&b, "type %s_v%s interface {\n", strings.ToCamelCase(pkg), service.Version.StringSpecial("_"))
+	for i, method := range service.Methods {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeDocs(&b, "\t", method.Docs)
+		for _, tag := range methodTags(method) {
+			fmt.Fprintf(&b, "\t// %s\n", tag)
+		}
+		var args, results []string
+		args = append(args, "ctx context.Context")
+		for _, arg := range method.Arguments {
+			args = append(args, arg.Name+" "+goType(arg.Type))
+		}
+		for _, result := range method.Results {
+			results = append(results, result.Name+" "+goType(result.Type))
+		}
+		results = append(results, "err error")
+		fmt.Fprintf(&b, "\t%s(%s) (%s)\n", method.Name, strs.Join(args, ", "), strs.Join(results, ", "))
+	}
+	b.WriteString("}\n")
+	return format.Source([]byte(b.String()))
+}
+
+func methodTags(method types.Method) (tags []string) {
+	if method.Options.HTTP.Method != "" && method.Options.HTTP.Method != "POST" {
+		tags = append(tags, fmt.Sprintf("@http-method(%s)", method.Options.HTTP.Method))
+	}
+	if method.Options.HTTP.AbsURI != "" {
+		tags = append(tags, fmt.Sprintf("@http-abs-URI(%s)", method.Options.HTTP.AbsURI))
+	}
+	origins := map[string][]string{}
+	for _, arg := range method.Arguments {
+		if arg.Options.HTTP.Origin != "" && arg.Options.HTTP.Origin != "BODY" {
+			origins[arg.Options.HTTP.Origin] = append(origins[arg.Options.HTTP.Origin], arg.Name)
+		}
+	}
+	var keys []string
+	for origin := range origins {
+		keys = append(keys, origin)
+	}
+	sort.Strings(keys)
+	for _, origin := range keys {
+		tags = append(tags, fmt.Sprintf("@params([%s], (@http-origin(%s)))", strs.Join(origins[origin], ", "), origin))
+	}
+	for _, arg := range method.Arguments {
+		if arg.Alias != "" {
+			tags = append(tags, fmt.Sprintf("@alias(%s, %s)", arg.Name, arg.Alias))
+		}
+	}
+	for _, result := range method.Results {
+		if result.Alias != "" {
+			tags = append(tags, fmt.Sprintf("@alias(%s, %s)", result.Name, result.Alias))
+		}
+	}
+	return
+}
+
+func goType(t *types.Type) (s string) {
+	if t.IsSlice {
+		s += "[]"
+	}
+	if t.IsPointer {
+		s += "*"
+	}
+	if t.IsMap {
+		return s + "map[" + t.Name + "]" + goType(t.Value)
+	}
+	if t.IsBytes {
+		return s + "[]byte"
+	}
+	if t.IsImport {
+		return s + t.Pkg + "." + t.Name
+	}
+	return s + t.Name
+}
+
+func usesTime(service types.Service) bool {
+	var uses func(t *types.Type) bool
+	uses = func(t *types.Type) bool {
+		return t != nil && (t.IsImport && t.PkgImportPath == "time" || t.IsMap && uses(t.Value))
+	}
+	for _, entity := range service.Entities {
+		for _, field := range entity.Fields {
+			if uses(field.Type) {
+				return true
+			}
+		}
+	}
+	for _, method := range service.Methods {
+		for _, arg := range method.Arguments {
+			if uses(arg.Type) {
+				return true
+			}
+		}
+		for _, result := range method.Results {
+			if uses(result.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeDocs(b *strs.Builder, indent string, docs []string) {
+	for _, doc := range docs {
+		for _, line := range strs.Split(strs.TrimSpace(doc), "\n") {
+			fmt.Fprintf(b, "%s// %s\n", indent, strs.TrimSpace(line))
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package lsp
+
+import (
+	"fmt"
+	"go/ast"
+	goParser "go/parser"
+	"go/token"
+	"path/filepath"
+	strs "strings"
+
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser"
+	"github.com/wlMalk/goms/parser/tags"
+)
+
+const (
+	kindService = "service"
+	kindMethod  = "method"
+	kindParam   = "param"
+)
+
+// tagContext is where the cursor is in the docs of a service interface or of
+// one of its methods.
+type tagContext struct {
+	kind   string
+	method *ast.FuncType
+	text   string
+}
+
+type frame struct {
+	tag  string
+	args int
+}
+
+func (s *Server) context(path string, pos position) (*tagContext, int) {
+	src, err := s.source(path)
+	if err != nil {
+		return nil, 0
+	}
+	fset := token.NewFileSet()
+	f, _ := goParser.ParseFile(fset, path, src, goParser.ParseComments|goParser.AllErrors)
+	if f == nil {
+		return nil, 0
+	}
+	off := offset(src, pos)
+	p := f.Pos() + token.Pos(off)
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			ifaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = genDecl.Doc
+			}
+			if contains(doc, p) {
+				return &tagContext{kind: kindService, text: lineBefore(src, off)}, off
+			}
+			for _, field := range ifaceType.Methods.List {
+				if funcType, ok := field.Type.(*ast.FuncType); ok && contains(field.Doc, p) {
+					return &tagContext{kind: kindMethod, method: funcType, text: lineBefore(src, off)}, off
+				}
+			}
+		}
+	}
+	return nil, off
+}
+
+func contains(doc *ast.CommentGroup, p token.Pos) bool {
+	return doc != nil && doc.Pos() <= p && p <= doc.End()
+}
+
+func lineBefore(src []byte, off int) string {
+	start := strs.LastIndexByte(string(src[:off]), '\n') + 1
+	return string(src[start:off])
+}
+
+// scan walks the text of the line before the cursor, returning the tag name
+// being typed, if any, and the stack of the tags whose parentheses are open.
+func scan(text string) (word string, inTag bool, stack []frame) {
+	for _, r := range text {
+		switch {
+		case r == '@':
+			inTag, word = true, ""
+		case inTag && isTagRune(r):
+			word += string(r)
+		case r == '(':
+			if inTag {
+				stack = append(stack, frame{tag: strs.ToLower(word)})
+			} else {
+				stack = append(stack, frame{})
+			}
+			inTag = false
+		case r == '[':
+			stack = append(stack, frame{tag: "["})
+			inTag = false
+		case r == ')' || r == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			inTag = false
+		case r == ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].args++
+			}
+			inTag = false
+		default:
+			inTag = false
+		}
+	}
+	return
+}
+
+func isTagRune(r rune) bool {
+	return r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+func inParams(stack []frame) bool {
+	for i := range stack {
+		if stack[i].tag == "params" && i+1 < len(stack) && stack[i+1].tag == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) complete(path string, pos position) (items []completionItem) {
+	ctx, _ := s.context(path, pos)
+	if ctx == nil {
+		return nil
+	}
+	p, _ := s.parser(filepath.Dir(path))
+	word, inTag, stack := scan(ctx.text)
+	kind := ctx.kind
+	if inParams(stack) {
+		kind = kindParam
+	}
+	if inTag {
+		for _, name := range tagNames(p, kind) {
+			if strs.HasPrefix(name, strs.ToLower(word)) {
+				items = append(items, completionItem{Label: name, Kind: completionKindKeyword, Detail: kind + " tag", InsertText: name})
+			}
+		}
+		return
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	top := stack[len(stack)-1]
+	if top.tag == "[" {
+		if len(stack) > 1 && stack[len(stack)-2].tag == "params" {
+			return valueItems(argumentNames(ctx.method, false), completionKindVariable)
+		}
+		top = frame{}
+		if len(stack) > 1 {
+			top = stack[len(stack)-2]
+		}
+	}
+	return valueItems(tagValues(p, kind, top, ctx.method), completionKindValue)
+}
+
+func valueItems(values []string, kind int) (items []completionItem) {
+	for _, v := range values {
+		items = append(items, completionItem{Label: v, Kind: kind})
+	}
+	return
+}
+
+func tagNames(p *parser.Parser, kind string) []string {
+	switch kind {
+	case kindService:
+		return p.ServiceTags()
+	case kindMethod:
+		return p.MethodTags()
+	}
+	return p.ParamTags()
+}
+
+func tagValues(p *parser.Parser, kind string, f frame, method *ast.FuncType) []string {
+	switch kind + ":" + f.tag {
+	case "service:transports", "method:transports":
+		return tags.Transports
+	case "service:metrics", "method:metrics":
+		return tags.Metrics
+	case "service:generate", "service:generate-all":
+		return p.ServiceGenerateFlags()
+	case "method:enable", "method:disable", "method:enable-all", "method:disable-all":
+		return p.MethodGenerateFlags()
+	case "method:http-method":
+		return tags.HTTPMethods
+	case "method:http-stream":
+		return tags.HTTPStreams
+	case "method:logs-ignore", "method:logs-len":
+		return append(argumentNames(method, true), "err")
+	case "method:alias":
+		if f.args == 0 {
+			return argumentNames(method, true)
+		}
+	case "method:validate":
+		if f.args == 0 {
+			return argumentNames(method, false)
+		}
+		return tags.Validators
+	case "param:http-origin":
+		return tags.HTTPOrigins
+	}
+	if schema, ok := tagSchema(p, kind, f.tag); ok {
+		var values []string
+		if f.args < len(schema.Args) {
+			switch arg := schema.Args[f.args]; arg.Type {
+			case parser.TagArgEnum:
+				values = append(values, arg.Values...)
+			case parser.TagArgBool:
+				values = append(values, "true", "false")
+			}
+		}
+		for _, arg := range schema.Args {
+			values = append(values, arg.Name+"=")
+		}
+		return values
+	}
+	return nil
+}
+
+func tagSchema(p *parser.Parser, kind string, name string) (parser.TagSchema, bool) {
+	switch kind {
+	case kindService:
+		return p.ServiceTagSchema(name)
+	case kindMethod:
+		return p.MethodTagSchema(name)
+	}
+	return p.ParamTagSchema(name)
+}
+
+// argumentNames returns the names of the arguments, without the context, and
+// of the results, without the error, as they are written in tags.
+func argumentNames(method *ast.FuncType, results bool) (names []string) {
+	if method == nil {
+		return nil
+	}
+	collect := func(list *ast.FieldList, skipFirst bool, skipLast bool) {
+		if list == nil {
+			return
+		}
+		var all []string
+		for _, field := range list.List {
+			for _, name := range field.Names {
+				all = append(all, name.Name)
+			}
+		}
+		if skipFirst && len(all) > 0 {
+			all = all[1:]
+		}
+		if skipLast && len(all) > 0 {
+			all = all[:len(all)-1]
+		}
+		for _, name := range all {
+			names = append(names, strings.ToLowerFirst(strings.ToCamelCase(name)))
+		}
+	}
+	collect(method.Params, true, false)
+	if results {
+		collect(method.Results, false, true)
+	}
+	return
+}
+
+func (s *Server) hover(path string, pos position) *hover {
+	ctx, off := s.context(path, pos)
+	if ctx == nil {
+		return nil
+	}
+	src, _ := s.source(path)
+	start, end := off, off
+	for start > 0 && isTagRune(rune(src[start-1])) {
+		start--
+	}
+	for end < len(src) && isTagRune(rune(src[end])) {
+		end++
+	}
+	if start == 0 || src[start-1] != '@' || start == end {
+		return nil
+	}
+	name := strs.ToLower(string(src[start:end]))
+	_, _, stack := scan(ctx.text[:len(ctx.text)-(off-start)])
+	kind := ctx.kind
+	if inParams(stack) {
+		kind = kindParam
+	}
+	doc := tagDoc(s, path, kind, name)
+	if doc == "" {
+		return nil
+	}
+	return &hover{Contents: markupContent{Kind: "markdown", Value: doc}}
+}
+
+func tagDoc(s *Server, path string, kind string, name string) string {
+	p, _ := s.parser(filepath.Dir(path))
+	var doc string
+	switch kind {
+	case kindService:
+		doc = p.ServiceTagDoc(name)
+	case kindMethod:
+		doc = p.MethodTagDoc(name)
+	default:
+		doc = p.ParamTagDoc(name)
+	}
+	if doc != "" {
+		return doc
+	}
+	known := false
+	for _, tag := range tagNames(p, kind) {
+		known = known || tag == name
+	}
+	if !known {
+		return ""
+	}
+	doc = fmt.Sprintf("`@%s` is a custom %s tag.", name, kind)
+	if schema, ok := tagSchema(p, kind, name); ok && len(schema.Args) > 0 {
+		doc += "\n\nArguments:"
+		for _, arg := range schema.Args {
+			doc += fmt.Sprintf("\n- `%s` %s", arg.Name, arg.Type)
+			if arg.Type == parser.TagArgEnum {
+				doc += " (" + strs.Join(arg.Values, ", ") + ")"
+			}
+			if arg.Required {
+				doc += ", required"
+			}
+		}
+	}
+	return doc
+}
@@ -0,0 +1,122 @@
+package lsp
+
+import (
+	"path/filepath"
+	"reflect"
+	strs "strings"
+	"testing"
+
+	"github.com/wlMalk/goms/parser"
+	"github.com/wlMalk/goms/parser/tags"
+)
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		text  string
+		word  string
+		inTag bool
+		stack []frame
+	}{
+		{text: "// @val", word: "val", inTag: true},
+		{text: "// @validate(", word: "validate", stack: []frame{{tag: "validate"}}},
+		{text: "// @validate(name, min-len(", word: "validate", stack: []frame{{tag: "validate", args: 1}, {}}},
+		{text: "// @validate(name, min-len(3)) @Tra", word: "Tra", inTag: true, stack: []frame{}},
+		{text: "// @params([a, ", word: "params", stack: []frame{{tag: "params"}, {tag: "[", args: 1}}},
+		{text: "// @params([a], (@http", word: "http", inTag: true, stack: []frame{{tag: "params", args: 1}, {}}},
+	}
+	for _, test := range tests {
+		word, inTag, stack := scan(test.text)
+		if word != test.word || inTag != test.inTag || !reflect.DeepEqual(stack, test.stack) {
+			t.Errorf("%s: got %q, %t, %v, want %q, %t, %v", test.text, word, inTag, stack, test.word, test.inTag, test.stack)
+		}
+	}
+}
+
+const testSource = `package svc
+
+import "context"
+
+// @generate(
+// @transports(
+type Svc_v1 interface {
+	// @val
+	// @validate(
+	// @validate(name,
+	// @http-method(
+	// @params([
+	// @params([name], (@http-origin(
+	// @params([name], (@
+	CreateUser(ctx context.Context, name string, userAge int) (id string, err error)
+}
+`
+
+func testComplete(t *testing.T, s *Server, path string, line int) (labels []string) {
+	t.Helper()
+	text := strs.Split(testSource, "\n")[line]
+	for _, item := range s.complete(path, position{Line: line, Character: len(text)}) {
+		labels = append(labels, item.Label)
+	}
+	return
+}
+
+func TestComplete(t *testing.T) {
+	s, _ := testServer()
+	path := filepath.Join(t.TempDir(), "service.go")
+	s.docs[path] = testSource
+	p := parser.Default()
+	tests := []struct {
+		line int
+		want []string
+	}{
+		{line: 4, want: p.ServiceGenerateFlags()},
+		{line: 5, want: tags.Transports},
+		{line: 7, want: []string{"validate"}},
+		{line: 8, want: []string{"name", "userAge"}},
+		{line: 9, want: tags.Validators},
+		{line: 10, want: tags.HTTPMethods},
+		{line: 11, want: []string{"name", "userAge"}},
+		{line: 12, want: tags.HTTPOrigins},
+		{line: 13, want: p.ParamTags()},
+		{line: 14},
+	}
+	for _, test := range tests {
+		if got := testComplete(t, s, path, test.line); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", strs.TrimSpace(strs.Split(testSource, "\n")[test.line]), got, test.want)
+		}
+	}
+}
+
+func TestHover(t *testing.T) {
+	s, _ := testServer()
+	path := filepath.Join(t.TempDir(), "service.go")
+	s.docs[path] = testSource
+	h := s.hover(path, position{Line: 8, Character: 7})
+	if h == nil || h.Contents.Value != tags.MethodValidateTagDoc {
+		t.Errorf("got %v, want the docs of the validate tag", h)
+	}
+	if h := s.hover(path, position{Line: 4, Character: 7}); h == nil || h.Contents.Value != tags.ServiceGenerateTagDoc {
+		t.Errorf("got %v, want the docs of the generate tag", h)
+	}
+	if h := s.hover(path, position{Line: 14, Character: 4}); h != nil {
+		t.Errorf("got %v, want nothing outside of tags", h)
+	}
+}
+
+func TestBuiltInTagsDocs(t *testing.T) {
+	p := parser.Default()
+	for _, name := range p.ServiceTags() {
+		if p.ServiceTagDoc(name) == "" {
+			t.Errorf("service tag '%s' has no docs", name)
+		}
+	}
+	for _, name := range p.MethodTags() {
+		if p.MethodTagDoc(name) == "" {
+			t.Errorf("method tag '%s' has no docs", name)
+		}
+	}
+	for _, name := range p.ParamTags() {
+		if p.ParamTagDoc(name) == "" {
+			t.Errorf("param tag '%s' has no docs", name)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"go/ast"
+	goParser "go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	strs "strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/wlMalk/goms/config"
+	"github.com/wlMalk/goms/parser"
+	"github.com/wlMalk/goms/version"
+)
+
+type Server struct {
+	conn *conn
+	docs map[string]string
+}
+
+// Serve runs a language server reading requests from r and writing responses
+// to w until the client exits or r is closed.
+func Serve(r io.Reader, w io.Writer) error {
+	s := &Server{
+		conn: &conn{r: bufio.NewReader(r), w: w},
+		docs: map[string]string{},
+	}
+	for {
+		msg, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) error {
+	var result interface{}
+	var resErr *responseError
+	switch msg.Method {
+	case "initialize":
+		result = map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": 1,
+				"completionProvider": map[string]interface{}{
+					"triggerCharacters": []string{"@", "(", ",", "["},
+				},
+				"hoverProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "goms", "version": version.VERSION},
+		}
+	case "shutdown":
+		result = nil
+	case "textDocument/didOpen":
+		params := &didOpenParams{}
+		if err := json.Unmarshal(msg.Params, params); err == nil {
+			path := uriToPath(params.TextDocument.URI)
+			s.docs[path] = params.TextDocument.Text
+			return s.diagnose(filepath.Dir(path))
+		}
+	case "textDocument/didChange":
+		params := &didChangeParams{}
+		if err := json.Unmarshal(msg.Params, params); err == nil && len(params.ContentChanges) > 0 {
+			path := uriToPath(params.TextDocument.URI)
+			s.docs[path] = params.ContentChanges[len(params.ContentChanges)-1].Text
+			return s.diagnose(filepath.Dir(path))
+		}
+	case "textDocument/didSave":
+		params := &didSaveParams{}
+		if err := json.Unmarshal(msg.Params, params); err == nil {
+			path := uriToPath(params.TextDocument.URI)
+			if _, ok := s.docs[path]; ok && params.Text != nil {
+				s.docs[path] = *params.Text
+			}
+			return s.diagnose(filepath.Dir(path))
+		}
+	case "textDocument/didClose":
+		params := &didCloseParams{}
+		if err := json.Unmarshal(msg.Params, params); err == nil {
+			path := uriToPath(params.TextDocument.URI)
+			delete(s.docs, path)
+			return s.diagnose(filepath.Dir(path))
+		}
+	case "textDocument/completion", "textDocument/hover":
+		params := &textDocumentPositionParams{}
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			resErr = &responseError{Code: codeInvalidParams, Message: err.Error()}
+			break
+		}
+		path := uriToPath(params.TextDocument.URI)
+		if msg.Method == "textDocument/completion" {
+			items := s.complete(path, params.Position)
+			if items == nil {
+				items = []completionItem{}
+			}
+			result = items
+		} else if h := s.hover(path, params.Position); h != nil {
+			result = h
+		}
+	default:
+		if msg.ID != nil && !strs.HasPrefix(msg.Method, "$/") {
+			resErr = &responseError{Code: codeMethodNotFound, Message: "method '" + msg.Method + "' is not supported"}
+		}
+	}
+	if msg.ID == nil {
+		return nil
+	}
+	res := &message{ID: msg.ID, Error: resErr}
+	if resErr == nil {
+		res.Result = json.RawMessage("null")
+		if result != nil {
+			res.Result = result
+		}
+	}
+	return s.conn.write(res)
+}
+
+func (s *Server) source(path string) ([]byte, error) {
+	if src, ok := s.docs[path]; ok {
+		return []byte(src), nil
+	}
+	return ioutil.ReadFile(path)
+}
+
+func (s *Server) packageFiles(dir string) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	seen := map[string]bool{}
+	var files []string
+	for _, match := range append(matches, s.openFiles(dir)...) {
+		if !seen[match] && !strs.HasSuffix(match, "_test.go") {
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func (s *Server) openFiles(dir string) (files []string) {
+	for path := range s.docs {
+		if filepath.Dir(path) == dir && strs.HasSuffix(path, ".go") {
+			files = append(files, path)
+		}
+	}
+	return
+}
+
+func (s *Server) parser(dir string, opts ...parser.ParserOption) (*parser.Parser, error) {
+	root := dir
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			root = d
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if p := config.Find(dir, root); p != "" {
+		cfg, err := config.Load(p)
+		if err != nil {
+			return parser.Default(opts...), err
+		}
+		opts = append(cfg.ParserOptions(), opts...)
+	}
+	return parser.Default(opts...), nil
+}
+
+func (s *Server) diagnose(dir string) error {
+	files := s.packageFiles(dir)
+	fset := token.NewFileSet()
+	var astFiles []*ast.File
+	var errs scanner.ErrorList
+	for _, path := range files {
+		src, err := s.source(path)
+		if err != nil {
+			continue
+		}
+		f, err := goParser.ParseFile(fset, path, src, goParser.ParseComments|goParser.AllErrors)
+		if list, ok := err.(scanner.ErrorList); ok {
+			errs = append(errs, list...)
+		}
+		if f != nil && err == nil {
+			astFiles = append(astFiles, f)
+		}
+	}
+	var general []string
+	if len(errs) == 0 && len(astFiles) > 0 {
+		p, err := s.parser(dir, parser.FileSet(fset))
+		if err != nil {
+			general = append(general, err.Error())
+		}
+		_, err = p.ParseFiles(astFiles...)
+		if list, ok := err.(scanner.ErrorList); ok {
+			for _, e := range list {
+				if e.Msg == parser.ErrNoServices.Error() {
+					errs, general = nil, nil
+					break
+				}
+				errs = append(errs, e)
+			}
+		} else if err != nil {
+			general = append(general, err.Error())
+		}
+	}
+	diagnostics := map[string][]diagnostic{}
+	for _, e := range errs {
+		if e.Pos.Filename == "" {
+			general = append(general, e.Msg)
+			continue
+		}
+		src, _ := s.source(e.Pos.Filename)
+		diagnostics[e.Pos.Filename] = append(diagnostics[e.Pos.Filename], diagnostic{
+			Range:    errorRange(src, e.Pos),
+			Severity: severityError,
+			Source:   "goms",
+			Message:  e.Msg,
+		})
+	}
+	for _, msg := range general {
+		for _, path := range s.openFiles(dir) {
+			diagnostics[path] = append(diagnostics[path], diagnostic{Severity: severityError, Source: "goms", Message: msg})
+		}
+	}
+	for _, path := range files {
+		if diagnostics[path] == nil {
+			diagnostics[path] = []diagnostic{}
+		}
+		if err := s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         pathToURI(path),
+			Diagnostics: diagnostics[path],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorRange covers the word starting at the position of the error, which is
+// usually a tag, an argument or a field name.
+func errorRange(src []byte, pos token.Position) rangeType {
+	line := lineText(src, pos.Line-1)
+	start := pos.Column - 1
+	if start < 0 || start > len(line) {
+		start = 0
+	}
+	end := start
+	for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+		end++
+	}
+	if end == start {
+		end = len(line)
+	}
+	return rangeType{
+		Start: position{Line: pos.Line - 1, Character: utf16Len(line[:start])},
+		End:   position{Line: pos.Line - 1, Character: utf16Len(line[:end])},
+	}
+}
+
+func lineText(src []byte, line int) string {
+	lines := strs.Split(string(src), "\n")
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+	return strs.TrimSuffix(lines[line], "\r")
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += len(utf16.Encode([]rune{r}))
+	}
+	return n
+}
+
+// offset converts an LSP position to a byte offset in src.
+func offset(src []byte, pos position) int {
+	off := 0
+	for line := 0; line < pos.Line; line++ {
+		i := strs.IndexByte(string(src[off:]), '\n')
+		if i == -1 {
+			return len(src)
+		}
+		off += i + 1
+	}
+	for n := 0; n < pos.Character && off < len(src) && src[off] != '\n'; {
+		r, size := utf8.DecodeRune(src[off:])
+		n += len(utf16.Encode([]rune{r}))
+		off += size
+	}
+	return off
+}
@@ -0,0 +1,128 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"path/filepath"
+	"testing"
+)
+
+func TestOffset(t *testing.T) {
+	src := []byte("ab\n😀c\n\nd")
+	tests := []struct {
+		pos  position
+		want int
+	}{
+		{position{Line: 0, Character: 0}, 0},
+		{position{Line: 0, Character: 2}, 2},
+		{position{Line: 0, Character: 9}, 2},
+		{position{Line: 1, Character: 2}, 7},
+		{position{Line: 1, Character: 3}, 8},
+		{position{Line: 2, Character: 0}, 9},
+		{position{Line: 3, Character: 1}, 11},
+		{position{Line: 9, Character: 0}, 11},
+	}
+	for _, test := range tests {
+		if got := offset(src, test.pos); got != test.want {
+			t.Errorf("%v: got %d, want %d", test.pos, got, test.want)
+		}
+	}
+}
+
+func TestErrorRange(t *testing.T) {
+	src := []byte("package p\n\t// 😀 @http-method(FETCH) x\n")
+	got := errorRange(src, token.Position{Line: 2, Column: 10})
+	want := rangeType{Start: position{Line: 1, Character: 7}, End: position{Line: 1, Character: 26}}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	got = errorRange(src, token.Position{Line: 2, Column: 40})
+	if want := (rangeType{Start: position{Line: 1}, End: position{Line: 1, Character: 28}}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func testServer() (*Server, *bytes.Buffer) {
+	out := new(bytes.Buffer)
+	return &Server{conn: &conn{w: out}, docs: map[string]string{}}, out
+}
+
+func testNotify(t *testing.T, s *Server, method string, params interface{}) {
+	t.Helper()
+	b, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.handle(&message{Method: method, Params: b}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testDiagnostics returns the diagnostics published by the server, by file.
+func testDiagnostics(t *testing.T, out *bytes.Buffer) map[string][]diagnostic {
+	t.Helper()
+	c := &conn{r: bufio.NewReader(out)}
+	diagnostics := map[string][]diagnostic{}
+	for out.Len() > 0 {
+		msg, err := c.read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		params := &publishDiagnosticsParams{}
+		if msg.Method != "textDocument/publishDiagnostics" || json.Unmarshal(msg.Params, params) != nil {
+			t.Fatalf("unexpected message %v", msg)
+		}
+		diagnostics[uriToPath(params.URI)] = params.Diagnostics
+	}
+	return diagnostics
+}
+
+func TestDiagnostics(t *testing.T) {
+	s, out := testServer()
+	path := filepath.Join(t.TempDir(), "service.go")
+	uri := pathToURI(path)
+	src := "package svc\n\nimport \"context\"\n\n// @generate(middleware)\ntype Svc_v1 interface {\n\t// @http-method(FETCH)\n\tA(ctx context.Context, name string) (err error)\n}\n"
+	testNotify(t, s, "textDocument/didOpen", didOpenParams{TextDocument: textDocumentItem{URI: uri, Text: src}})
+	got := testDiagnostics(t, out)[path]
+	want := []diagnostic{{
+		Range:    rangeType{Start: position{Line: 6, Character: 4}, End: position{Line: 6, Character: 23}},
+		Severity: severityError,
+		Source:   "goms",
+		Message:  "invalid http-method value 'FETCH'",
+	}}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	fixed := bytes.Replace([]byte(src), []byte("FETCH"), []byte("PUT"), 1)
+	testNotify(t, s, "textDocument/didChange", map[string]interface{}{
+		"textDocument":   textDocumentIdentifier{URI: uri},
+		"contentChanges": []map[string]string{{"text": string(fixed)}},
+	})
+	if got, ok := testDiagnostics(t, out)[path]; !ok || len(got) != 0 {
+		t.Fatalf("expected the diagnostics to be cleared, got %v", got)
+	}
+
+	text := "package svc\n\ntype Svc_v1 interface {"
+	testNotify(t, s, "textDocument/didSave", didSaveParams{TextDocument: textDocumentIdentifier{URI: uri}, Text: &text})
+	if got := testDiagnostics(t, out)[path]; len(got) == 0 || got[0].Range.Start.Line != 2 {
+		t.Fatalf("expected a syntax error, got %v", got)
+	}
+}
+
+func TestHandleUnknownMethod(t *testing.T) {
+	s, out := testServer()
+	id := json.RawMessage("1")
+	if err := s.handle(&message{ID: &id, Method: "workspace/symbol"}); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := (&conn{r: bufio.NewReader(out)}).read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Error == nil || msg.Error.Code != codeMethodNotFound {
+		t.Fatalf("got %v, want a method not found error", msg)
+	}
+}
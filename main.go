@@ -2,75 +2,56 @@ package main
 
 import (
 	"fmt"
-	goParser "go/parser"
-	"go/token"
+	"go/scanner"
 	"os"
-	"path/filepath"
-	"strings"
 
-	"github.com/wlMalk/goms/generator"
-	"github.com/wlMalk/goms/parser"
 	"github.com/wlMalk/goms/version"
 
 	"github.com/gookit/color"
 )
 
 func main() {
-	color.New(color.FgBlack, color.BgWhite, color.Bold).Printf("  GoMS  ")
-	fmt.Printf(" v%s", version.VERSION)
-	fmt.Println("")
-	var err error
-	defer func() {
-		if err == nil {
-			success(fmt.Sprintf("All files are successfully generated"))
-		}
-	}()
 	defer func() {
 		if err := recover(); err != nil {
 			fail(fmt.Errorf("%s", err))
 		}
 	}()
-	currentDir, err := os.Getwd()
-	if err != nil {
-		fail(err)
+	args := os.Args[1:]
+	if len(args) == 0 || len(args[0]) > 0 && args[0][0] == '-' {
+		args = append([]string{"generate"}, args...)
 	}
-	goPath := os.Getenv("GOPATH")
-	if strings.TrimSpace(goPath) == "" {
-		fail(fmt.Errorf("GOPATH is not defined"))
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		if len(args) > 1 {
+			if cmd := findCommand(args[1]); cmd != nil {
+				cmd.flagSet().Usage()
+				return
+			}
+		}
+		usage()
+		return
 	}
-	if !filepath.HasPrefix(currentDir, goPath) {
-		fail(fmt.Errorf("service has to be located inside GOPATH"))
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		usage()
+		fail(fmt.Errorf("unknown command '%s'", args[0]))
 	}
-	importPath, err := filepath.Rel(filepath.Join(os.Getenv("GOPATH"), "./src/"), currentDir)
-	if err != nil {
+	if err := cmd.run(args[1:]); err != nil {
 		fail(err)
 	}
-	path := filepath.Join(currentDir, "./service.go")
-	fset := token.NewFileSet()
-	f, err := goParser.ParseFile(fset, path, nil, goParser.ParseComments|goParser.AllErrors)
-	if err != nil {
-		fail(fmt.Errorf("error when parse file: %v", err))
-	}
+}
 
-	p := parser.Default()
-	g := generator.Default()
+func banner() {
+	color.New(color.FgBlack, color.BgWhite, color.Bold).Printf("  GoMS  ")
+	fmt.Printf(" v%s", version.VERSION)
+	fmt.Println("")
+}
 
-	services, err := p.Parse(f)
-	if err != nil {
-		fail(err)
-	}
-	for _, service := range services {
-		service.Path = filepath.Join(currentDir, "v"+service.Version.FullStringSpecial("."))
-		service.ImportPath = filepath.ToSlash(filepath.Join(importPath, "v"+service.Version.FullStringSpecial(".")))
-		files, err := g.Generate(service)
-		if err != nil {
-			fail(err)
-		}
-		err = files.Save()
-		if err != nil {
-			fail(err)
-		}
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: goms <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands() {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.short)
 	}
+	fmt.Fprintf(os.Stderr, "\nRun 'goms help <command>' for more information about a command.\n")
 }
 
 func success(s string) {
@@ -79,9 +60,23 @@ func success(s string) {
 	color.Green.Printf("%s\n", s)
 }
 
+func warn(s string) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", color.BgYellow.Sprint("  WARN  "), color.Yellow.Sprint(s))
+}
+
 func fail(err error) {
+	report(err)
+	os.Exit(2)
+}
+
+func report(err error) {
+	if list, ok := err.(scanner.ErrorList); ok && len(list) > 1 {
+		for _, e := range list {
+			fmt.Fprintln(os.Stderr, e)
+		}
+		err = fmt.Errorf("%d problems were found", len(list))
+	}
 	color.BgRed.Print("  FAIL  ")
 	fmt.Print(" ")
 	color.Red.Printf("%s\n", err.Error())
-	os.Exit(2)
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func importPathOf(dir string) (string, error) {
+	modRoot, modPath, err := findModule(dir)
+	if err != nil {
+		return "", err
+	}
+	if modRoot != "" {
+		rel, err := filepath.Rel(modRoot, dir)
+		if err != nil {
+			return "", err
+		}
+		return path.Join(modPath, filepath.ToSlash(rel)), nil
+	}
+	return goPathImportPath(dir)
+}
+
+func findModule(dir string) (root string, modulePath string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		modFile := filepath.Join(dir, "go.mod")
+		if info, err := os.Stat(modFile); err == nil && !info.IsDir() {
+			modulePath, err = readModulePath(modFile)
+			if err != nil {
+				return "", "", err
+			}
+			return dir, modulePath, nil
+		} else if err != nil && !os.IsNotExist(err) {
+			return "", "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+func readModulePath(modFile string) (string, error) {
+	f, err := os.Open(modFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+		modulePath := fields[1]
+		if strings.HasPrefix(modulePath, "\"") || strings.HasPrefix(modulePath, "`") {
+			modulePath, err = strconv.Unquote(modulePath)
+			if err != nil {
+				return "", fmt.Errorf("invalid module path in '%s': %v", modFile, err)
+			}
+		}
+		return modulePath, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no module path is declared in '%s'", modFile)
+}
+
+func goPathImportPath(dir string) (string, error) {
+	goPath := os.Getenv("GOPATH")
+	if strings.TrimSpace(goPath) == "" {
+		return "", fmt.Errorf("service has to be located inside a Go module or GOPATH, but no go.mod was found and GOPATH is not defined")
+	}
+	for _, p := range filepath.SplitList(goPath) {
+		src := filepath.Join(p, "src")
+		rel, err := filepath.Rel(src, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return filepath.ToSlash(rel), nil
+	}
+	return "", fmt.Errorf("service has to be located inside a Go module or GOPATH")
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/wlMalk/goms/parser/types"
@@ -9,6 +10,7 @@ import (
 
 type generateHandler struct {
 	allowed types.GenerateList
+	optIn   types.GenerateList
 	groups  map[string][]string
 }
 
@@ -26,8 +28,23 @@ func (err *errGenerateInvalidValue) Error() string {
 	return fmt.Sprintf("invalid value '%s'", err.invalidValue)
 }
 
+func (m *generateHandler) names() (names []string) {
+	names = append(names, m.allowed...)
+	for group := range m.groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+	return
+}
+
+// all adds every allowed flag to g except the opt-in ones, which are only
+// generated when asked for explicitly.
 func (m *generateHandler) all(g *types.GenerateList) {
-	m.copy(g, m.allowed)
+	for _, a := range m.allowed {
+		if !m.optIn.Has(a) {
+			g.Add(a)
+		}
+	}
 }
 
 func (m *generateHandler) allBut(g *types.GenerateList, options ...string) error {
@@ -88,6 +105,11 @@ func (m *generateHandler) addAllowed(a ...string) {
 	m.allowed.Add(a...)
 }
 
+func (m *generateHandler) addOptIn(a ...string) {
+	m.allowed.Add(a...)
+	m.optIn.Add(a...)
+}
+
 func (m *generateHandler) groupAllowed(name string, a ...string) {
 	m.allowed.Add(a...)
 	for i := range a {
@@ -5,6 +5,7 @@ import (
 	strs "strings"
 	"unicode/utf8"
 
+	"github.com/wlMalk/goms/constants"
 	"github.com/wlMalk/goms/generator/strings"
 	"github.com/wlMalk/goms/parser/types"
 
@@ -15,16 +16,81 @@ func (p *Parser) setUpMethodFromService(s *types.Service, m *types.Method) {
 	p.methodGenerateFlagsHandler.copy(&m.Generate, s.Generate)
 }
 
-func validateMethod(m *types.Method) error {
-	{
-		if m.Options.HTTP.Method != "POST" && m.Options.HTTP.Method != "PUT" && m.Options.HTTP.Method != "PATCH" {
-			for _, arg := range m.Arguments {
-				if arg.Options.HTTP.Origin == "BODY" {
-					return fmt.Errorf("invalid http origin 'BODY' for '%s' argument in '%s' method with '%s' verb", arg.Name, m.Name, m.Options.HTTP.Method)
+// streamingUnsupportedFlags returns the generate flags dropped from a
+// streaming method, whose values cannot be cached nor sent over JSON-RPC,
+// message queues or plain HTTP unless it is tagged with http-stream.
+func streamingUnsupportedFlags(m *types.Method) []string {
+	flags := []string{
+		constants.MethodGenerateJSONRPCServerFlag,
+		constants.MethodGenerateJSONRPCClientFlag,
+		constants.MethodGenerateMQServerFlag,
+		constants.MethodGenerateMQClientFlag,
+		constants.MethodGenerateCachingFlag,
+	}
+	if m.Options.HTTP.Stream != "" {
+		return flags
+	}
+	return append(flags,
+		constants.MethodGenerateHTTPServerFlag,
+		constants.MethodGenerateHTTPClientFlag,
+	)
+}
+
+func validateMethodArgument(m *types.Method, arg *types.Argument) error {
+	if m.Options.HTTP.Method != "POST" && m.Options.HTTP.Method != "PUT" && m.Options.HTTP.Method != "PATCH" {
+		if arg.Options.HTTP.Origin == "BODY" {
+			return fmt.Errorf("invalid http origin 'BODY' for '%s' argument in '%s' method with '%s' verb", arg.Name, m.Name, m.Options.HTTP.Method)
+		}
+	}
+	return nil
+}
+
+func (p *Parser) validateValidators(iface string, s *types.Service) {
+	for _, m := range s.Methods {
+		for _, arg := range m.Arguments {
+			if arg.Type.IsStream && len(arg.Options.Validators) > 0 {
+				p.errorAt(p.docPos(iface, m.Name, "@validate("+strings.ToLowerFirst(arg.Name)), fmt.Errorf("streamed argument '%s' in '%s' method cannot be validated", arg.Name, m.Name))
+				continue
+			}
+			for _, v := range arg.Options.Validators {
+				if v.Name != constants.ValidatorEnum {
+					continue
+				}
+				pos := p.docPos(iface, m.Name, "@validate("+strings.ToLowerFirst(arg.Name))
+				if len(v.Args) == 0 && (!arg.Type.IsEnum || arg.Type.IsImport) {
+					p.errorAt(pos, fmt.Errorf("invalid validator 'enum' for '%s' argument in '%s' method: argument is not an enum", arg.Name, m.Name))
+				}
+				if len(v.Args) > 0 && arg.Type.IsEnum {
+					p.errorAt(pos, fmt.Errorf("invalid validator 'enum' for '%s' argument in '%s' method: enum arguments take no values", arg.Name, m.Name))
 				}
 			}
 		}
 	}
+}
+
+func (p *Parser) validatePublishedEvents(iface string, s *types.Service) {
+	for _, m := range s.Methods {
+		for _, entity := range m.Options.Events.Publishes {
+			if publishedType(m, entity) == nil {
+				p.errorAt(p.docPos(iface, m.Name, "@publishes("), fmt.Errorf("invalid entity '%s' given to publishes method tag in '%s' method: no result or argument is a single value of this entity declared in the service", entity, m.Name))
+			}
+		}
+	}
+}
+
+// publishedType returns the type of the result, or else the argument, which
+// the method publishes as the given entity.
+func publishedType(m types.Method, entity string) *types.Type {
+	for _, result := range m.Results {
+		if result.Type.IsPublishable(entity) {
+			return result.Type
+		}
+	}
+	for _, arg := range m.Arguments {
+		if arg.Type.IsPublishable(entity) {
+			return arg.Type
+		}
+	}
 	return nil
 }
 
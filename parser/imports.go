@@ -0,0 +1,252 @@
+package parser
+
+import (
+	"fmt"
+	"go/build"
+	goTypes "go/types"
+	"os"
+	"path/filepath"
+	strs "strings"
+
+	"github.com/wlMalk/goms/parser/types"
+
+	"github.com/fatih/structtag"
+	"golang.org/x/tools/go/packages"
+)
+
+func ResolveImportedEntities(dir string) ParserOption {
+	return func(parser *Parser) {
+		parser.importsDir = dir
+		parser.resolveImports = true
+	}
+}
+
+type importedEntities struct {
+	pkgs     map[string]*packages.Package
+	entities map[string]*types.Entity
+	order    []*types.Entity
+}
+
+func (p *Parser) resolveImportedEntities(service *types.Service) error {
+	var imported []*types.Type
+	var collect func(t *types.Type)
+	collect = func(t *types.Type) {
+		if t == nil {
+			return
+		}
+		if t.IsMap {
+			collect(t.Value)
+			return
+		}
+		if t.IsImport && !isStandardPackage(t.PkgImportPath) {
+			imported = append(imported, t)
+		}
+	}
+	for _, method := range service.Methods {
+		for _, arg := range method.Arguments {
+			collect(arg.Type)
+		}
+		for _, res := range method.Results {
+			collect(res.Type)
+		}
+	}
+	for _, e := range service.Entities {
+		for _, field := range e.Fields {
+			collect(field.Type)
+		}
+	}
+	for _, ag := range service.ArgumentsGroups {
+		for _, arg := range ag.Arguments {
+			collect(arg.Type)
+		}
+	}
+	if len(imported) == 0 {
+		return nil
+	}
+	var paths []string
+	for _, t := range imported {
+		if !contains(paths, t.PkgImportPath) {
+			paths = append(paths, t.PkgImportPath)
+		}
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes,
+		Dir:  p.importsDir,
+	}
+	pkgs, err := packages.Load(cfg, paths...)
+	if err != nil {
+		return fmt.Errorf("cannot load imported packages: %v", err)
+	}
+	ie := &importedEntities{
+		pkgs:     map[string]*packages.Package{},
+		entities: map[string]*types.Entity{},
+	}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return fmt.Errorf("cannot load package '%s': %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		ie.pkgs[pkg.PkgPath] = pkg
+	}
+	for _, t := range imported {
+		pkg, ok := ie.pkgs[t.PkgImportPath]
+		if !ok {
+			return fmt.Errorf("cannot load package '%s'", t.PkgImportPath)
+		}
+		obj := pkg.Types.Scope().Lookup(t.Name)
+		if obj == nil {
+			return fmt.Errorf("type '%s' is not declared in package '%s'", t.Name, t.PkgImportPath)
+		}
+		named, ok := obj.Type().(*goTypes.Named)
+		if !ok {
+			continue
+		}
+		e, err := ie.entity(named)
+		if err != nil {
+			return err
+		}
+		if e != nil {
+			t.Entity = e
+			t.IsEntity = true
+		}
+	}
+	for _, e := range ie.order {
+		service.Entities = append(service.Entities, *e)
+	}
+	return nil
+}
+
+// validateEntityNames reports the imported entities named like another
+// entity, as the generated protobuf messages and converters are named after
+// the entities only.
+func (p *Parser) validateEntityNames(iface string, service *types.Service) {
+	names := map[string]string{}
+	for _, e := range service.Entities {
+		name := e.Name
+		if e.IsImport {
+			name = e.Pkg + "." + e.Name
+		}
+		other, ok := names[e.Name]
+		if !ok {
+			names[e.Name] = name
+			continue
+		}
+		pos := p.typePos(iface)
+		if !strs.Contains(other, ".") {
+			pos = p.typePos(other)
+		}
+		p.errorAt(pos, fmt.Errorf("imported entity '%s' has the same name as entity '%s', entities must have unique names", name, other))
+	}
+}
+
+func (ie *importedEntities) entity(named *goTypes.Named) (*types.Entity, error) {
+	obj := named.Obj()
+	if obj.Pkg() == nil || isStandardPackage(obj.Pkg().Path()) {
+		return nil, nil
+	}
+	st, ok := named.Underlying().(*goTypes.Struct)
+	if !ok {
+		return nil, nil
+	}
+	key := obj.Pkg().Path() + "." + obj.Name()
+	if e, ok := ie.entities[key]; ok {
+		return e, nil
+	}
+	e := &types.Entity{
+		Name:          obj.Name(),
+		IsImport:      true,
+		Pkg:           obj.Pkg().Name(),
+		PkgImportPath: obj.Pkg().Path(),
+	}
+	ie.entities[key] = e
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		if !v.Exported() {
+			continue
+		}
+		field := &types.Field{Name: v.Name()}
+		t, err := ie.fieldType(v.Type())
+		if err != nil {
+			return nil, fmt.Errorf("field '%s' of '%s': %v", v.Name(), key, err)
+		}
+		field.Type = t
+		if tags, err := structtag.Parse(st.Tag(i)); err == nil && tags.Len() > 0 {
+			field.Tags = map[string][]string{}
+			for _, tag := range tags.Tags() {
+				field.Tags[tag.Key] = append([]string{tag.Name}, tag.Options...)
+			}
+		}
+		e.Fields = append(e.Fields, field)
+	}
+	ie.order = append(ie.order, e)
+	return e, nil
+}
+
+func (ie *importedEntities) fieldType(typ goTypes.Type) (*types.Type, error) {
+	t := &types.Type{}
+	switch tt := typ.(type) {
+	case *goTypes.Slice:
+		if b, ok := tt.Elem().(*goTypes.Basic); ok && b.Kind() == goTypes.Byte {
+			t.IsBytes = true
+			t.Name = "byte"
+			return t, nil
+		}
+		t.IsSlice = true
+		typ = tt.Elem()
+	case *goTypes.Map:
+		key, ok := tt.Key().(*goTypes.Basic)
+		if !ok || !acceptableBuiltin(key.Name()) || key.Info()&goTypes.IsFloat != 0 {
+			return nil, fmt.Errorf("unsupported map key type %s", tt.Key().String())
+		}
+		t.IsMap = true
+		t.Name = key.Name()
+		value, err := ie.fieldType(tt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if value.IsSlice || value.IsMap {
+			return nil, fmt.Errorf("unsupported map value type %s", tt.Elem().String())
+		}
+		t.Value = value
+		return t, nil
+	}
+	if p, ok := typ.(*goTypes.Pointer); ok {
+		t.IsPointer = true
+		typ = p.Elem()
+	}
+	switch tt := typ.(type) {
+	case *goTypes.Basic:
+		if t.IsPointer || !acceptableBuiltin(tt.Name()) {
+			return nil, fmt.Errorf("unsupported type %s", typ.String())
+		}
+		t.Name = tt.Name()
+		t.IsBuiltin = true
+	case *goTypes.Named:
+		obj := tt.Obj()
+		if obj.Pkg() == nil {
+			return nil, fmt.Errorf("unsupported type %s", typ.String())
+		}
+		t.Name = obj.Name()
+		t.IsImport = true
+		t.Pkg = obj.Pkg().Name()
+		t.PkgImportPath = obj.Pkg().Path()
+		e, err := ie.entity(tt)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			t.Entity = e
+			t.IsEntity = true
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ.String())
+	}
+	return t, nil
+}
+
+func isStandardPackage(importPath string) bool {
+	if strs.Contains(strs.Split(importPath, "/")[0], ".") {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(importPath)))
+	return err == nil && info.IsDir()
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"regexp"
 	"strconv"
 	strs "strings"
@@ -15,92 +16,140 @@ import (
 	astTypes "github.com/vetcher/go-astra/types"
 )
 
+var ErrNoServices = errors.New("no service definitions were found")
+
 var versionPattern = regexp.MustCompile(`(?is)^v?([0-9]+)((\.|-|_|:)([0-9]+))?((\.|-|_|:)([0-9]+))?$`)
 
 func (p *Parser) Parse(f *ast.File) (services []types.Service, err error) {
-	file, err := astra.ParseAstFile(f)
-	if err != nil {
-		return nil, err
-	}
-	enums, err := p.parseEnums(f)
-	if err != nil {
-		return nil, err
-	}
-	entities, err := p.parseEntities(file)
-	if err != nil {
-		return nil, err
-	}
-	argumentsGroups, err := p.parseArgumentsGroups(file)
+	return p.ParseFiles(f)
+}
+
+// ParseFiles parses the services defined in the files, reporting all the
+// problems found at once as a scanner.ErrorList sorted by position.
+func (p *Parser) ParseFiles(fs ...*ast.File) (services []types.Service, err error) {
+	p.files, p.errs = fs, nil
+	file, err := parseAstFiles(fs...)
 	if err != nil {
 		return nil, err
 	}
+	enums := p.parseEnums(fs...)
+	entities := p.parseEntities(file)
+	argumentsGroups := p.parseArgumentsGroups(file)
 	serviceName := cleanServiceName(file.Name)
 	ifaces := getServiceInterfaces(file.Interfaces, serviceName)
 	if len(ifaces) == 0 {
-		return nil, errors.New("no service definitions were found")
+		p.errorAt(token.NoPos, ErrNoServices)
 	}
 	for _, iface := range ifaces {
-		s, err := p.parseService(iface, serviceName)
-		if err != nil {
-			return nil, err
+		s := p.parseService(iface, serviceName)
+		if s == nil {
+			continue
+		}
+		used := usedTypes(s, entities, argumentsGroups)
+		p.setServiceEnums(s, enums, used)
+		p.setServiceEntities(s, entities, used)
+		p.setServiceArgumentsGroups(s, argumentsGroups, used)
+		p.setServiceTypes(s)
+		p.validateValidators(iface.Name, s)
+		p.validatePublishedEvents(iface.Name, s)
+		if p.resolveImports && len(p.errs) == 0 {
+			if err := p.resolveImportedEntities(s); err != nil {
+				p.errorAt(p.typePos(iface.Name), err)
+			} else {
+				p.validateEntityNames(iface.Name, s)
+			}
 		}
-		p.setServiceEnums(s, enums)
-		p.setServiceEntities(s, entities)
-		p.setServiceArgumentsGroups(s, argumentsGroups)
 		services = append(services, *s)
 	}
+	if len(p.errs) > 0 {
+		p.errs.Sort()
+		return nil, p.errs
+	}
 	return
 }
 
-func (p *Parser) parseService(iface *astTypes.Interface, serviceName string) (*types.Service, error) {
+func parseAstFiles(fs ...*ast.File) (*astTypes.File, error) {
+	if len(fs) == 0 {
+		return nil, errors.New("no files were given to parse")
+	}
+	var file *astTypes.File
+	for _, f := range fs {
+		parsed, err := astra.ParseAstFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			file = parsed
+			continue
+		}
+		if parsed.Name != file.Name {
+			return nil, fmt.Errorf("found files of different packages '%s' and '%s'", file.Name, parsed.Name)
+		}
+		file.Imports = append(file.Imports, parsed.Imports...)
+		file.Constants = append(file.Constants, parsed.Constants...)
+		file.Vars = append(file.Vars, parsed.Vars...)
+		file.Interfaces = append(file.Interfaces, parsed.Interfaces...)
+		file.Structures = append(file.Structures, parsed.Structures...)
+		file.Functions = append(file.Functions, parsed.Functions...)
+		file.Methods = append(file.Methods, parsed.Methods...)
+		file.Types = append(file.Types, parsed.Types...)
+	}
+	return file, nil
+}
+
+func (p *Parser) parseService(iface *astTypes.Interface, serviceName string) *types.Service {
 	s := defaultService()
 	s.Name = serviceName
 	ver, err := ParseVersion(extractServiceVersion(iface.Name, serviceName))
 	if err != nil {
-		return nil, err
+		p.errorAt(p.typePos(iface.Name), err)
+		return nil
 	}
 	s.Version = *ver
+	if err := p.serviceGenerateFlagsHandler.add(&s.Generate, p.defaultServiceGenerate...); err != nil {
+		if errInvalid, ok := err.(*errGenerateInvalidValue); ok {
+			err = fmt.Errorf("invalid default generate value '%s' for '%s' service", errInvalid.invalidValue, s.Name)
+		}
+		p.errorAt(p.typePos(iface.Name), err)
+	}
 	var ts []string
 	ts, s.Docs = cleanComments(iface.Docs)
-	err = p.parseServiceTags(s, ts)
-	if err != nil {
-		return nil, err
+	for _, err := range p.parseServiceTags(s, ts) {
+		p.tagErrorAt(iface.Name, "", err)
 	}
 	for _, method := range iface.Methods {
-		m, ts, err := p.parseMethod(method)
-		if err != nil {
-			return nil, err
-		}
+		errs := len(p.errs)
+		m, ts := p.parseMethod(iface.Name, method)
 		p.setUpMethodFromService(s, m)
-		err = p.parseMethodTags(m, ts)
-		if err != nil {
-			return nil, err
+		for _, err := range p.parseMethodTags(m, ts) {
+			p.tagErrorAt(iface.Name, method.Name, err)
 		}
-		if err := validateMethod(m); err != nil {
-			return nil, err
+		if m.IsStreaming() {
+			m.Generate.Remove(streamingUnsupportedFlags(m)...)
+		}
+		if len(p.errs) == errs {
+			for i, arg := range m.Arguments {
+				if err := validateMethodArgument(m, arg); err != nil {
+					p.errorAt(p.paramPos(iface.Name, method.Name, i+1, false), err)
+				}
+			}
 		}
 		s.Methods = append(s.Methods, *m)
 	}
-	return s, nil
+	return s
 }
 
-func (p *Parser) parseMethod(method *astTypes.Function) (*types.Method, []string, error) {
+func (p *Parser) parseMethod(iface string, method *astTypes.Function) (*types.Method, []string) {
 	m := defaultMethod()
 	m.Name = method.Name
-	err := p.parseArguments(m, method.Args)
-	if err != nil {
-		return nil, nil, err
-	}
-	err = p.parseResults(m, method.Results)
-	if err != nil {
-		return nil, nil, err
-	}
+	p.parseArguments(iface, m, method.Args)
+	p.parseResults(iface, m, method.Results)
 	var ts []string
 	ts, m.Docs = cleanComments(method.Docs)
-	return m, ts, nil
+	return m, ts
 }
 
-func (p *Parser) parseArguments(m *types.Method, args []astTypes.Variable) error {
+func (p *Parser) parseArguments(iface string, m *types.Method, args []astTypes.Variable) {
 	for i, arg := range args {
 		if i == 0 {
 			firstIsContext := true
@@ -113,23 +162,29 @@ func (p *Parser) parseArguments(m *types.Method, args []astTypes.Variable) error
 				firstIsContext = false
 			}
 			if !firstIsContext {
-				return fmt.Errorf("first argument in \"%s\" method has to be of type \"context.Context\" from package \"context\"", m.Name)
+				p.errorAt(p.paramPos(iface, m.Name, i, false), fmt.Errorf("first argument in \"%s\" method has to be of type \"context.Context\" from package \"context\"", m.Name))
 			}
 		} else {
 			a, err := p.parseArgument(arg)
 			if err != nil {
-				return err
+				p.errorAt(p.paramPos(iface, m.Name, i, false), err)
+				continue
 			}
 			if a.Name == "" {
-				return fmt.Errorf("'%s' method has an unnamed argument", m.Name)
+				p.errorAt(p.paramPos(iface, m.Name, i, false), fmt.Errorf("'%s' method has an unnamed argument", m.Name))
+				continue
 			}
 			if err := validateArgument(a); err != nil {
-				return err
+				p.errorAt(p.paramPos(iface, m.Name, i, false), err)
+				continue
+			}
+			if a.Type.IsStream && len(args) > 2 {
+				p.errorAt(p.paramPos(iface, m.Name, i, false), fmt.Errorf("streamed argument '%s' has to be the only argument of '%s' method", a.Name, m.Name))
+				continue
 			}
 			m.Arguments = append(m.Arguments, a)
 		}
 	}
-	return nil
 }
 
 func (p *Parser) parseArgument(v astTypes.Variable) (*types.Argument, error) {
@@ -143,28 +198,34 @@ func (p *Parser) parseArgument(v astTypes.Variable) (*types.Argument, error) {
 	return a, nil
 }
 
-func (p *Parser) parseResults(m *types.Method, args []astTypes.Variable) error {
+func (p *Parser) parseResults(iface string, m *types.Method, args []astTypes.Variable) {
 	for i, arg := range args {
 		if i == len(args)-1 {
 			t, ok := arg.Type.(astTypes.TName)
 			if !ok || t.TypeName != "error" {
-				return fmt.Errorf("last return value in \"%s\" method has to be of type \"error\"", m.Name)
+				p.errorAt(p.paramPos(iface, m.Name, i, true), fmt.Errorf("last return value in \"%s\" method has to be of type \"error\"", m.Name))
 			}
 		} else {
 			r, err := p.parseField(arg)
 			if err != nil {
-				return err
+				p.errorAt(p.paramPos(iface, m.Name, i, true), err)
+				continue
 			}
 			if r.Name == "" {
-				return fmt.Errorf("'%s' method has an unnamed return value", m.Name)
+				p.errorAt(p.paramPos(iface, m.Name, i, true), fmt.Errorf("'%s' method has an unnamed return value", m.Name))
+				continue
 			}
 			if err := validateField(r); err != nil {
-				return err
+				p.errorAt(p.paramPos(iface, m.Name, i, true), err)
+				continue
+			}
+			if r.Type.IsStream && len(args) > 2 {
+				p.errorAt(p.paramPos(iface, m.Name, i, true), fmt.Errorf("streamed result '%s' has to be the only result of '%s' method", r.Name, m.Name))
+				continue
 			}
 			m.Results = append(m.Results, r)
 		}
 	}
-	return nil
 }
 
 func (p *Parser) parseField(v astTypes.Variable) (*types.Field, error) {
@@ -178,56 +239,58 @@ func (p *Parser) parseField(v astTypes.Variable) (*types.Field, error) {
 	return f, nil
 }
 
-func (p *Parser) parseServiceTags(service *types.Service, tags []string) error {
+func (p *Parser) parseServiceTags(service *types.Service, tags []string) (errs []error) {
 	for _, tag := range tags {
 		builtInTag := extractTag(p.getBuiltInServiceTags(), tag)
 		otherTag := extractTag(p.getOtherServiceTags(), tag)
 		if len(builtInTag) > len(otherTag) {
 			parser := p.builtInServiceTags[strs.ToLower(builtInTag)]
 			if err := parser(service, cleanTag(strs.TrimPrefix(tag, "@"+builtInTag))); err != nil {
-				return err
+				errs = append(errs, &tagError{tag, err})
 			}
 			continue
 		} else if len(builtInTag) < len(otherTag) {
 			parser := p.otherServiceTags[strs.ToLower(otherTag)]
 			options := types.TagOptions{}
 			if err := parser(*service, options, cleanTag(strs.TrimPrefix(tag, "@"+otherTag))); err != nil {
-				return err
+				errs = append(errs, &tagError{tag, fmt.Errorf("invalid service tag '%s': %v", otherTag, err)})
+				continue
 			}
 			if len(options) > 0 {
 				service.OtherOptions[strings.ToLower(otherTag)] = options
 			}
 			continue
 		}
-		return fmt.Errorf("invalid service tag \"%s\"", tag)
+		errs = append(errs, &tagError{tag, fmt.Errorf("invalid service tag \"%s\"", tag)})
 	}
-	return nil
+	return
 }
 
-func (p *Parser) parseMethodTags(method *types.Method, tags []string) error {
+func (p *Parser) parseMethodTags(method *types.Method, tags []string) (errs []error) {
 	for _, tag := range tags {
 		builtInTag := extractTag(p.getBuiltInMethodTags(), tag)
 		otherTag := extractTag(p.getOtherMethodTags(), tag)
 		if len(builtInTag) > len(otherTag) {
 			parser := p.builtInMethodTags[strs.ToLower(builtInTag)]
 			if err := parser(method, cleanTag(strs.TrimPrefix(tag, "@"+builtInTag))); err != nil {
-				return err
+				errs = append(errs, &tagError{tag, err})
 			}
 			continue
 		} else if len(builtInTag) < len(otherTag) {
 			parser := p.otherMethodTags[strs.ToLower(otherTag)]
 			options := types.TagOptions{}
 			if err := parser(*method, options, cleanTag(strs.TrimPrefix(tag, "@"+otherTag))); err != nil {
-				return err
+				errs = append(errs, &tagError{tag, fmt.Errorf("invalid method tag '%s': %v", otherTag, err)})
+				continue
 			}
 			if len(options) > 0 {
 				method.OtherOptions[strings.ToLower(otherTag)] = options
 			}
 			continue
 		}
-		return fmt.Errorf("invalid method tag \"%s\"", tag)
+		errs = append(errs, &tagError{tag, fmt.Errorf("invalid method tag \"%s\"", tag)})
 	}
-	return nil
+	return
 }
 
 func (p *Parser) parseParamTags(param *types.Argument, tags []string) error {
@@ -237,37 +300,36 @@ func (p *Parser) parseParamTags(param *types.Argument, tags []string) error {
 		if len(builtInTag) > len(otherTag) {
 			parser := p.builtInParamTags[strs.ToLower(builtInTag)]
 			if err := parser(param, cleanTag(strs.TrimPrefix(tag, "@"+builtInTag))); err != nil {
-				return err
+				return &tagError{tag, err}
 			}
 			continue
 		} else if len(builtInTag) < len(otherTag) {
 			parser := p.otherParamTags[strs.ToLower(otherTag)]
 			options := types.TagOptions{}
 			if err := parser(*param, options, cleanTag(strs.TrimPrefix(tag, "@"+otherTag))); err != nil {
-				return err
+				return &tagError{tag, fmt.Errorf("invalid param tag '%s': %v", otherTag, err)}
 			}
 			if len(options) > 0 {
 				param.OtherOptions[strings.ToLower(otherTag)] = options
 			}
 			continue
 		}
-		return fmt.Errorf("invalid param tag \"%s\"", tag)
+		return &tagError{tag, fmt.Errorf("invalid param tag \"%s\"", tag)}
 	}
 	return nil
 }
 
-func (p *Parser) parseEnums(file *ast.File) (enums []types.Enum, err error) {
-	for _, t := range file.Decls {
-		if g, ok := t.(*ast.GenDecl); ok && g.Tok.String() == "type" {
-			for _, s := range g.Specs {
-				if ts, ok := s.(*ast.TypeSpec); ok && fmt.Sprint(ts.Type) == "int" && ts.Name.IsExported() {
-					e := types.Enum{}
-					e.Name = ts.Name.String()
-					e.Cases, err = p.parseEnumCases(file, e.Name)
-					if err != nil {
-						return nil, err
+func (p *Parser) parseEnums(files ...*ast.File) (enums []types.Enum) {
+	for _, file := range files {
+		for _, t := range file.Decls {
+			if g, ok := t.(*ast.GenDecl); ok && g.Tok.String() == "type" {
+				for _, s := range g.Specs {
+					if ts, ok := s.(*ast.TypeSpec); ok && fmt.Sprint(ts.Type) == "int" && ts.Name.IsExported() {
+						e := types.Enum{}
+						e.Name = ts.Name.String()
+						e.Cases = p.parseEnumCases(files, e.Name)
+						enums = append(enums, e)
 					}
-					enums = append(enums, e)
 				}
 			}
 		}
@@ -275,26 +337,30 @@ func (p *Parser) parseEnums(file *ast.File) (enums []types.Enum, err error) {
 	return
 }
 
-func (p *Parser) parseEnumCases(file *ast.File, enum string) (cases []types.EnumCase, err error) {
-	for _, t := range file.Decls {
-		if g, ok := t.(*ast.GenDecl); ok && g.Tok.String() == "const" {
-			for _, s := range g.Specs {
-				if ts, ok := s.(*ast.ValueSpec); ok && fmt.Sprint(ts.Type) == enum {
-					if len(ts.Names) != len(ts.Values) {
-						return nil, fmt.Errorf("cannot parse '%s' enum values", enum)
-					}
-					for i := range ts.Names {
-						if bv, ok := ts.Values[i].(*ast.BasicLit); ts.Names[i].IsExported() && ok {
-							eCase := types.EnumCase{}
-							eCase.Name = ts.Names[i].String()
-							v, err := strconv.Atoi(bv.Value)
-							if err != nil {
-								return nil, fmt.Errorf("cannot parse '%s' value '%s' for '%s' enum", enum, eCase.Name, bv.Value)
+func (p *Parser) parseEnumCases(files []*ast.File, enum string) (cases []types.EnumCase) {
+	for _, file := range files {
+		for _, t := range file.Decls {
+			if g, ok := t.(*ast.GenDecl); ok && g.Tok.String() == "const" {
+				for _, s := range g.Specs {
+					if ts, ok := s.(*ast.ValueSpec); ok && fmt.Sprint(ts.Type) == enum {
+						if len(ts.Names) != len(ts.Values) {
+							p.errorAt(ts.Pos(), fmt.Errorf("cannot parse '%s' enum values", enum))
+							continue
+						}
+						for i := range ts.Names {
+							if bv, ok := ts.Values[i].(*ast.BasicLit); ts.Names[i].IsExported() && ok {
+								eCase := types.EnumCase{}
+								eCase.Name = ts.Names[i].String()
+								v, err := strconv.Atoi(bv.Value)
+								if err != nil {
+									p.errorAt(bv.Pos(), fmt.Errorf("cannot parse '%s' value '%s' for '%s' enum", enum, eCase.Name, bv.Value))
+									continue
+								}
+								eCase.Value = v
+								cases = append(cases, eCase)
+							} else {
+								p.errorAt(ts.Names[i].Pos(), fmt.Errorf("cannot parse '%s' enum values", enum))
 							}
-							eCase.Value = v
-							cases = append(cases, eCase)
-						} else {
-							return nil, fmt.Errorf("cannot parse '%s' enum values", enum)
 						}
 					}
 				}
@@ -304,7 +370,7 @@ func (p *Parser) parseEnumCases(file *ast.File, enum string) (cases []types.Enum
 	return
 }
 
-func (p *Parser) parseEntities(ast *astTypes.File) (entities []types.Entity, err error) {
+func (p *Parser) parseEntities(ast *astTypes.File) (entities []types.Entity) {
 	for _, t := range ast.Structures {
 		if !strs.HasSuffix(t.Name, "ArgumentsGroup") {
 			e := types.Entity{}
@@ -313,9 +379,11 @@ func (p *Parser) parseEntities(ast *astTypes.File) (entities []types.Entity, err
 				field := types.Field{}
 				field.Name = f.Name
 				field.Tags = f.Tags
+				var err error
 				field.Type, err = parseType(f.Variable.Type)
 				if err != nil {
-					return nil, err
+					p.errorAt(p.fieldPos(t.Name, f.Name), err)
+					continue
 				}
 				e.Fields = append(e.Fields, &field)
 			}
@@ -325,7 +393,7 @@ func (p *Parser) parseEntities(ast *astTypes.File) (entities []types.Entity, err
 	return
 }
 
-func (p *Parser) parseArgumentsGroups(ast *astTypes.File) (argumentsGroups []types.ArgumentsGroup, err error) {
+func (p *Parser) parseArgumentsGroups(ast *astTypes.File) (argumentsGroups []types.ArgumentsGroup) {
 	for _, t := range ast.Structures {
 		if strs.HasSuffix(t.Name, "ArgumentsGroup") {
 			ag := types.ArgumentsGroup{}
@@ -333,9 +401,11 @@ func (p *Parser) parseArgumentsGroups(ast *astTypes.File) (argumentsGroups []typ
 			for _, f := range t.Fields {
 				arg := types.Argument{}
 				arg.Name = f.Name
+				var err error
 				arg.Type, err = parseType(f.Variable.Type)
 				if err != nil {
-					return nil, err
+					p.errorAt(p.fieldPos(t.Name, f.Name), err)
+					continue
 				}
 				ag.Arguments = append(ag.Arguments, &arg)
 			}
@@ -345,80 +415,130 @@ func (p *Parser) parseArgumentsGroups(ast *astTypes.File) (argumentsGroups []typ
 	return
 }
 
-func (p *Parser) setServiceEnums(service *types.Service, enums []types.Enum) {
-	for _, e := range enums {
-		found := false
-		for i, method := range service.Methods {
-			for _, arg := range method.Arguments {
-				if arg.Type.Name == e.Name {
-					arg.Type.Enum = &e
-					arg.Type.IsEnum = true
-					found = true
+func usedTypes(service *types.Service, entities []types.Entity, argumentsGroups []types.ArgumentsGroup) map[string]bool {
+	used := map[string]bool{}
+	var visit func(t *types.Type)
+	visit = func(t *types.Type) {
+		if t == nil || t.IsImport || t.IsBuiltin {
+			return
+		}
+		if t.IsMap {
+			visit(t.Value)
+			return
+		}
+		if used[t.Name] {
+			return
+		}
+		used[t.Name] = true
+		for _, e := range entities {
+			if e.Name == t.Name {
+				for _, field := range e.Fields {
+					visit(field.Type)
 				}
 			}
-			for _, res := range method.Results {
-				if res.Type.Name == e.Name {
-					res.Type.Enum = &e
-					res.Type.IsEnum = true
-					found = true
+		}
+		for _, ag := range argumentsGroups {
+			if ag.Name+"ArgumentsGroup" == t.Name {
+				for _, arg := range ag.Arguments {
+					visit(arg.Type)
 				}
 			}
-			service.Methods[i] = method
 		}
-		if found {
+	}
+	for _, method := range service.Methods {
+		for _, arg := range method.Arguments {
+			visit(arg.Type)
+		}
+		for _, res := range method.Results {
+			visit(res.Type)
+		}
+	}
+	return used
+}
+
+func eachType(t *types.Type, fn func(t *types.Type)) {
+	fn(t)
+	if t.IsMap && t.Value != nil {
+		fn(t.Value)
+	}
+}
+
+func eachServiceType(service *types.Service, fn func(t *types.Type)) {
+	for _, method := range service.Methods {
+		for _, arg := range method.Arguments {
+			eachType(arg.Type, fn)
+		}
+		for _, res := range method.Results {
+			eachType(res.Type, fn)
+		}
+	}
+	for _, e := range service.Entities {
+		for _, field := range e.Fields {
+			eachType(field.Type, fn)
+		}
+	}
+	for _, ag := range service.ArgumentsGroups {
+		for _, arg := range ag.Arguments {
+			eachType(arg.Type, fn)
+		}
+	}
+}
+
+func (p *Parser) setServiceEnums(service *types.Service, enums []types.Enum, used map[string]bool) {
+	for _, e := range enums {
+		if used[e.Name] {
 			service.Enums = append(service.Enums, e)
 		}
 	}
 }
 
-func (p *Parser) setServiceEntities(service *types.Service, entities []types.Entity) {
+func (p *Parser) setServiceEntities(service *types.Service, entities []types.Entity, used map[string]bool) {
 	for _, e := range entities {
-		found := false
-		for i, method := range service.Methods {
-			for _, arg := range method.Arguments {
-				if arg.Type.Name == e.Name {
-					arg.Type.Entity = &e
-					arg.Type.IsEntity = true
-					found = true
-				}
-			}
-			for _, res := range method.Results {
-				if res.Type.Name == e.Name {
-					res.Type.Entity = &e
-					res.Type.IsEntity = true
-					found = true
-				}
-			}
-			service.Methods[i] = method
-		}
-		if found {
+		if used[e.Name] {
 			service.Entities = append(service.Entities, e)
 		}
 	}
 }
 
-func (p *Parser) setServiceArgumentsGroups(service *types.Service, argumentsGroups []types.ArgumentsGroup) {
+func (p *Parser) setServiceTypes(service *types.Service) {
+	eachServiceType(service, func(t *types.Type) {
+		if t.IsImport {
+			return
+		}
+		for i := range service.Enums {
+			if service.Enums[i].Name == t.Name {
+				t.Enum = &service.Enums[i]
+				t.IsEnum = true
+			}
+		}
+		for i := range service.Entities {
+			if service.Entities[i].Name == t.Name {
+				t.Entity = &service.Entities[i]
+				t.IsEntity = true
+			}
+		}
+	})
+}
+
+func (p *Parser) setServiceArgumentsGroups(service *types.Service, argumentsGroups []types.ArgumentsGroup, used map[string]bool) {
 	for _, ag := range argumentsGroups {
-		found := false
-		for i, method := range service.Methods {
+		ag := ag
+		for _, method := range service.Methods {
 			for _, arg := range method.Arguments {
 				if arg.Type.Name == ag.Name+"ArgumentsGroup" {
 					arg.Type.Name = ag.Name
 					arg.Type.ArgumentsGroup = &ag
 					arg.Type.IsArgumentsGroup = true
-					found = true
 				}
 			}
 			for _, res := range method.Results {
 				if res.Type.Name == ag.Name+"ArgumentsGroup" {
 					res.Type.ArgumentsGroup = &ag
 					res.Type.IsArgumentsGroup = true
-					found = true
 				}
 			}
-			service.Methods[i] = method
 		}
-		if found {
+		if used[ag.Name+"ArgumentsGroup"] {
 			service.ArgumentsGroups = append(service.ArgumentsGroups, ag)
 		}
 	}
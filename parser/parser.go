@@ -2,7 +2,11 @@ package parser
 
 import (
 	"fmt"
+	"go/ast"
+	"go/scanner"
+	"go/token"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/wlMalk/goms/constants"
@@ -31,34 +35,48 @@ type Parser struct {
 	otherMethodTags    map[string]MethodTagParser
 	builtInParamTags   map[string]paramTagParser
 	otherParamTags     map[string]ParamTagParser
+	tagSchemas         map[string]TagSchema
+	tagDocs            map[string]string
 
 	serviceGenerateFlagsHandler *generateHandler
 	methodGenerateFlagsHandler  *generateHandler
+
+	resolveImports bool
+	importsDir     string
+
+	defaultServiceGenerate []string
+
+	fset  *token.FileSet
+	files []*ast.File
+	errs  scanner.ErrorList
 }
 
 type ParserOption func(parser *Parser)
 
 func BuiltInServiceTagsParsers(parser *Parser) {
-	parser.registerServiceTagParser("name", tags.ServiceNameTag)
-	parser.registerServiceTagParser("transports", tags.ServiceTransportsTag)
-	parser.registerServiceTagParser("metrics", tags.ServiceMetricsTag)
-	parser.registerServiceTagParser("http-URI-prefix", tags.ServiceHTTPUriPrefixTag)
+	parser.registerServiceTagParser("name", tags.ServiceNameTag, tags.ServiceNameTagDoc)
+	parser.registerServiceTagParser("transports", tags.ServiceTransportsTag, tags.ServiceTransportsTagDoc)
+	parser.registerServiceTagParser("metrics", tags.ServiceMetricsTag, tags.ServiceMetricsTagDoc)
+	parser.registerServiceTagParser("http-URI-prefix", tags.ServiceHTTPUriPrefixTag, tags.ServiceHTTPUriPrefixTagDoc)
 }
 
 func BuiltInMethodTagsParsers(parser *Parser) {
-	parser.registerMethodTagParser("name", tags.MethodNameTag)
-	parser.registerMethodTagParser("transports", tags.MethodTransportsTag)
-	parser.registerMethodTagParser("metrics", tags.MethodMetricsTag)
-	parser.registerMethodTagParser("http-method", tags.MethodHTTPMethodTag)
-	parser.registerMethodTagParser("http-URI", tags.MethodHTTPUriTag)
-	parser.registerMethodTagParser("http-abs-URI", tags.MethodHTTPAbsUriTag)
-	parser.registerMethodTagParser("logs-ignore", tags.MethodLogsIgnoreTag)
-	parser.registerMethodTagParser("logs-len", tags.MethodLogsLenTag)
-	parser.registerMethodTagParser("alias", tags.MethodAliasTag)
+	parser.registerMethodTagParser("name", tags.MethodNameTag, tags.MethodNameTagDoc)
+	parser.registerMethodTagParser("transports", tags.MethodTransportsTag, tags.MethodTransportsTagDoc)
+	parser.registerMethodTagParser("metrics", tags.MethodMetricsTag, tags.MethodMetricsTagDoc)
+	parser.registerMethodTagParser("http-method", tags.MethodHTTPMethodTag, tags.MethodHTTPMethodTagDoc)
+	parser.registerMethodTagParser("http-URI", tags.MethodHTTPUriTag, tags.MethodHTTPUriTagDoc)
+	parser.registerMethodTagParser("http-abs-URI", tags.MethodHTTPAbsUriTag, tags.MethodHTTPAbsUriTagDoc)
+	parser.registerMethodTagParser("http-stream", tags.MethodHTTPStreamTag, tags.MethodHTTPStreamTagDoc)
+	parser.registerMethodTagParser("logs-ignore", tags.MethodLogsIgnoreTag, tags.MethodLogsIgnoreTagDoc)
+	parser.registerMethodTagParser("logs-len", tags.MethodLogsLenTag, tags.MethodLogsLenTagDoc)
+	parser.registerMethodTagParser("alias", tags.MethodAliasTag, tags.MethodAliasTagDoc)
+	parser.registerMethodTagParser("validate", tags.MethodValidateTag, tags.MethodValidateTagDoc)
+	parser.registerMethodTagParser("publishes", tags.MethodPublishesTag, tags.MethodPublishesTagDoc)
 }
 
 func BuiltInParamTagsParsers(parser *Parser) {
-	parser.registerParamTagParser("http-origin", tags.ParamHTTPOriginTag)
+	parser.registerParamTagParser("http-origin", tags.ParamHTTPOriginTag, tags.ParamHTTPOriginTagDoc)
 }
 
 func BuiltInServiceGenerateFlags(parser *Parser) {
@@ -86,6 +104,13 @@ func BuiltInServiceGenerateFlags(parser *Parser) {
 		constants.ServiceGenerateGRPCClientFlag,
 		constants.ServiceGenerateDockerfileFlag,
 	)
+	parser.RegisterServiceGenerateOptInFlags(
+		constants.ServiceGenerateOpenAPIFlag,
+		constants.ServiceGenerateJSONRPCServerFlag,
+		constants.ServiceGenerateJSONRPCClientFlag,
+		constants.ServiceGenerateMQServerFlag,
+		constants.ServiceGenerateMQClientFlag,
+	)
 	parser.RegisterServiceGenerateFlagsGroup(constants.ServiceGenerateGroupMetrics,
 		constants.ServiceGenerateFrequencyMetricFlag,
 		constants.ServiceGenerateLatencyMetricFlag,
@@ -99,6 +124,14 @@ func BuiltInServiceGenerateFlags(parser *Parser) {
 		constants.ServiceGenerateGRPCServerFlag,
 		constants.ServiceGenerateGRPCClientFlag,
 	)
+	parser.RegisterServiceGenerateFlagsGroup(constants.ServiceGenerateGroupJSONRPC,
+		constants.ServiceGenerateJSONRPCServerFlag,
+		constants.ServiceGenerateJSONRPCClientFlag,
+	)
+	parser.RegisterServiceGenerateFlagsGroup(constants.ServiceGenerateGroupMQ,
+		constants.ServiceGenerateMQServerFlag,
+		constants.ServiceGenerateMQClientFlag,
+	)
 }
 
 func BuiltInMethodGenerateFlags(parser *Parser) {
@@ -121,6 +154,12 @@ func BuiltInMethodGenerateFlags(parser *Parser) {
 		constants.MethodGenerateGRPCServerFlag,
 		constants.MethodGenerateGRPCClientFlag,
 	)
+	parser.RegisterMethodGenerateOptInFlags(
+		constants.MethodGenerateJSONRPCServerFlag,
+		constants.MethodGenerateJSONRPCClientFlag,
+		constants.MethodGenerateMQServerFlag,
+		constants.MethodGenerateMQClientFlag,
+	)
 	parser.RegisterMethodGenerateFlagsGroup(constants.MethodGenerateGroupMetrics,
 		constants.MethodGenerateFrequencyMetricFlag,
 		constants.MethodGenerateLatencyMetricFlag,
@@ -134,6 +173,20 @@ func BuiltInMethodGenerateFlags(parser *Parser) {
 		constants.MethodGenerateGRPCServerFlag,
 		constants.MethodGenerateGRPCClientFlag,
 	)
+	parser.RegisterMethodGenerateFlagsGroup(constants.MethodGenerateGroupJSONRPC,
+		constants.MethodGenerateJSONRPCServerFlag,
+		constants.MethodGenerateJSONRPCClientFlag,
+	)
+	parser.RegisterMethodGenerateFlagsGroup(constants.MethodGenerateGroupMQ,
+		constants.MethodGenerateMQServerFlag,
+		constants.MethodGenerateMQClientFlag,
+	)
+}
+
+func DefaultServiceGenerateFlags(flags ...string) ParserOption {
+	return func(parser *Parser) {
+		parser.defaultServiceGenerate = append(parser.defaultServiceGenerate, flags...)
+	}
 }
 
 func New(opts ...ParserOption) *Parser {
@@ -144,18 +197,20 @@ func New(opts ...ParserOption) *Parser {
 		otherMethodTags:             map[string]MethodTagParser{},
 		builtInParamTags:            map[string]paramTagParser{},
 		otherParamTags:              map[string]ParamTagParser{},
+		tagSchemas:                  map[string]TagSchema{},
+		tagDocs:                     map[string]string{},
 		serviceGenerateFlagsHandler: newGenerateHandler(),
 		methodGenerateFlagsHandler:  newGenerateHandler(),
 	}
-	p.builtInMethodTags["params"] = p.methodParamsTag
+	p.registerMethodTagParser("params", p.methodParamsTag, tags.MethodParamsTagDoc)
 
-	p.builtInServiceTags["generate-all"] = p.serviceGenerateAllTag
-	p.builtInServiceTags["generate"] = p.serviceGenerateTag
+	p.registerServiceTagParser("generate-all", p.serviceGenerateAllTag, tags.ServiceGenerateAllTagDoc)
+	p.registerServiceTagParser("generate", p.serviceGenerateTag, tags.ServiceGenerateTagDoc)
 
-	p.builtInMethodTags["disable"] = p.methodDisableTag
-	p.builtInMethodTags["enable"] = p.methodEnableTag
-	p.builtInMethodTags["disable-all"] = p.methodDisableAllTag
-	p.builtInMethodTags["enable-all"] = p.methodEnableAllTag
+	p.registerMethodTagParser("disable", p.methodDisableTag, tags.MethodDisableTagDoc)
+	p.registerMethodTagParser("enable", p.methodEnableTag, tags.MethodEnableTagDoc)
+	p.registerMethodTagParser("disable-all", p.methodDisableAllTag, tags.MethodDisableAllTagDoc)
+	p.registerMethodTagParser("enable-all", p.methodEnableAllTag, tags.MethodEnableAllTagDoc)
 
 	for _, opt := range opts {
 		opt(p)
@@ -192,8 +247,9 @@ func (p *Parser) MustRegisterServiceTagParser(name string, parser ServiceTagPars
 
 }
 
-func (p *Parser) registerServiceTagParser(name string, parser serviceTagParser) {
+func (p *Parser) registerServiceTagParser(name string, parser serviceTagParser, doc string) {
 	p.builtInServiceTags[strings.ToLower(name)] = parser
+	p.tagDocs["service:"+strings.ToLower(name)] = doc
 }
 
 func (p *Parser) RegisterMethodTagParser(name string, parser MethodTagParser) error {
@@ -214,8 +270,9 @@ func (p *Parser) MustRegisterMethodTagParser(name string, parser MethodTagParser
 
 }
 
-func (p *Parser) registerMethodTagParser(name string, parser methodTagParser) {
+func (p *Parser) registerMethodTagParser(name string, parser methodTagParser, doc string) {
 	p.builtInMethodTags[strings.ToLower(name)] = parser
+	p.tagDocs["method:"+strings.ToLower(name)] = doc
 }
 
 func (p *Parser) RegisterParamTagParser(name string, parser ParamTagParser) error {
@@ -235,14 +292,19 @@ func (p *Parser) RegisterParamTagParser(name string, parser ParamTagParser) erro
 func (p *Parser) MustRegisterParamTagParser(name string, parser ParamTagParser) {
 }
 
-func (p *Parser) registerParamTagParser(name string, parser paramTagParser) {
+func (p *Parser) registerParamTagParser(name string, parser paramTagParser, doc string) {
 	p.builtInParamTags[strings.ToLower(name)] = parser
+	p.tagDocs["param:"+strings.ToLower(name)] = doc
 }
 
 func (p *Parser) RegisterServiceGenerateFlags(flags ...string) {
 	p.serviceGenerateFlagsHandler.addAllowed(flags...)
 }
 
+func (p *Parser) RegisterServiceGenerateOptInFlags(flags ...string) {
+	p.serviceGenerateFlagsHandler.addOptIn(flags...)
+}
+
 func (p *Parser) RegisterServiceGenerateFlagsGroup(group string, flags ...string) {
 	p.serviceGenerateFlagsHandler.groupAllowed(group, flags...)
 }
@@ -251,10 +313,69 @@ func (p *Parser) RegisterMethodGenerateFlags(flags ...string) {
 	p.methodGenerateFlagsHandler.addAllowed(flags...)
 }
 
+func (p *Parser) RegisterMethodGenerateOptInFlags(flags ...string) {
+	p.methodGenerateFlagsHandler.addOptIn(flags...)
+}
+
 func (p *Parser) RegisterMethodGenerateFlagsGroup(group string, flags ...string) {
 	p.methodGenerateFlagsHandler.groupAllowed(group, flags...)
 }
 
+func (p *Parser) ServiceTags() []string {
+	return sortedTags(p.getBuiltInServiceTags(), p.getOtherServiceTags())
+}
+
+func (p *Parser) MethodTags() []string {
+	return sortedTags(p.getBuiltInMethodTags(), p.getOtherMethodTags())
+}
+
+func (p *Parser) ParamTags() []string {
+	return sortedTags(p.getBuiltInParamTags(), p.getOtherParamTags())
+}
+
+func (p *Parser) ServiceGenerateFlags() []string {
+	return p.serviceGenerateFlagsHandler.names()
+}
+
+func (p *Parser) MethodGenerateFlags() []string {
+	return p.methodGenerateFlagsHandler.names()
+}
+
+func (p *Parser) ServiceTagSchema(name string) (TagSchema, bool) {
+	schema, ok := p.tagSchemas["service:"+strings.ToLower(name)]
+	return schema, ok
+}
+
+func (p *Parser) MethodTagSchema(name string) (TagSchema, bool) {
+	schema, ok := p.tagSchemas["method:"+strings.ToLower(name)]
+	return schema, ok
+}
+
+func (p *Parser) ParamTagSchema(name string) (TagSchema, bool) {
+	schema, ok := p.tagSchemas["param:"+strings.ToLower(name)]
+	return schema, ok
+}
+
+// ServiceTagDoc returns the documentation of a built-in service tag, or an
+// empty string for custom tags.
+func (p *Parser) ServiceTagDoc(name string) string {
+	return p.tagDocs["service:"+strings.ToLower(name)]
+}
+
+func (p *Parser) MethodTagDoc(name string) string {
+	return p.tagDocs["method:"+strings.ToLower(name)]
+}
+
+func (p *Parser) ParamTagDoc(name string) string {
+	return p.tagDocs["param:"+strings.ToLower(name)]
+}
+
+func sortedTags(builtIn []string, other []string) []string {
+	tags := append(builtIn, other...)
+	sort.Strings(tags)
+	return tags
+}
+
 func (p *Parser) getBuiltInServiceTags() (tags []string) {
 	for k := range p.builtInServiceTags {
 		tags = append(tags, k)
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	strs "strings"
+)
+
+func FileSet(fset *token.FileSet) ParserOption {
+	return func(parser *Parser) {
+		parser.fset = fset
+	}
+}
+
+type tagError struct {
+	tag string
+	err error
+}
+
+func (err *tagError) Error() string {
+	return err.err.Error()
+}
+
+func (p *Parser) errorAt(pos token.Pos, err error) {
+	if tagErr, ok := err.(*tagError); ok {
+		err = tagErr.err
+	}
+	if list, ok := err.(scanner.ErrorList); ok {
+		p.errs = append(p.errs, list...)
+		return
+	}
+	var position token.Position
+	if p.fset != nil && pos.IsValid() {
+		position = p.fset.Position(pos)
+	}
+	p.errs.Add(position, err.Error())
+}
+
+// tagErrorAt reports a tag error at the position of the failing tag in the
+// docs of the given interface, or of its method when method is not empty.
+func (p *Parser) tagErrorAt(iface string, method string, err error) {
+	tag := ""
+	if tagErr, ok := err.(*tagError); ok {
+		tag = tagErr.tag
+	}
+	p.errorAt(p.docPos(iface, method, tag), err)
+}
+
+// docPos returns the position of the first occurrence of s in the docs of the
+// interface or its method, falling back to the position of their names.
+func (p *Parser) docPos(iface string, method string, s string) token.Pos {
+	typeSpec, genDecl := p.findTypeSpec(iface)
+	if typeSpec == nil {
+		return token.NoPos
+	}
+	doc, pos := typeSpec.Doc, typeSpec.Name.Pos()
+	if doc == nil {
+		doc = genDecl.Doc
+	}
+	if method != "" {
+		field := p.findMethod(typeSpec, method)
+		if field == nil {
+			return pos
+		}
+		doc, pos = field.Doc, field.Names[0].Pos()
+	}
+	if doc == nil || s == "" {
+		return pos
+	}
+	for _, c := range doc.List {
+		if i := strs.Index(strs.Replace(c.Text, "\t", " ", -1), s); i != -1 {
+			return c.Pos() + token.Pos(i)
+		}
+	}
+	return pos
+}
+
+func (p *Parser) typePos(name string) token.Pos {
+	typeSpec, _ := p.findTypeSpec(name)
+	if typeSpec == nil {
+		return token.NoPos
+	}
+	return typeSpec.Name.Pos()
+}
+
+func (p *Parser) fieldPos(structName string, field string) token.Pos {
+	typeSpec, _ := p.findTypeSpec(structName)
+	if typeSpec == nil {
+		return token.NoPos
+	}
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return typeSpec.Name.Pos()
+	}
+	for _, f := range structType.Fields.List {
+		for _, name := range f.Names {
+			if name.Name == field {
+				return name.Pos()
+			}
+		}
+	}
+	return typeSpec.Name.Pos()
+}
+
+// paramPos returns the position of the i-th argument, or result when results
+// is set, of the method, counting the names of grouped parameters one by one.
+func (p *Parser) paramPos(iface string, method string, i int, results bool) token.Pos {
+	typeSpec, _ := p.findTypeSpec(iface)
+	if typeSpec == nil {
+		return token.NoPos
+	}
+	field := p.findMethod(typeSpec, method)
+	if field == nil {
+		return typeSpec.Name.Pos()
+	}
+	funcType, ok := field.Type.(*ast.FuncType)
+	if !ok {
+		return field.Pos()
+	}
+	list := funcType.Params
+	if results {
+		list = funcType.Results
+	}
+	if list == nil {
+		return field.Pos()
+	}
+	n := 0
+	for _, f := range list.List {
+		if len(f.Names) == 0 {
+			if n == i {
+				return f.Type.Pos()
+			}
+			n++
+			continue
+		}
+		for _, name := range f.Names {
+			if n == i {
+				return name.Pos()
+			}
+			n++
+		}
+	}
+	return field.Pos()
+}
+
+func (p *Parser) findTypeSpec(name string) (*ast.TypeSpec, *ast.GenDecl) {
+	for _, file := range p.files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == name {
+					return typeSpec, genDecl
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (p *Parser) findMethod(typeSpec *ast.TypeSpec, method string) *ast.Field {
+	ifaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+	if !ok {
+		return nil
+	}
+	for _, field := range ifaceType.Methods.List {
+		if len(field.Names) > 0 && field.Names[0].Name == method {
+			return field
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	strs "strings"
+	"time"
+
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+type TagArgType string
+
+const (
+	TagArgString   TagArgType = "string"
+	TagArgInt      TagArgType = "int"
+	TagArgBool     TagArgType = "bool"
+	TagArgDuration TagArgType = "duration"
+	TagArgList     TagArgType = "list"
+	TagArgEnum     TagArgType = "enum"
+)
+
+type TagArg struct {
+	Name     string
+	Type     TagArgType
+	Required bool
+	Values   []string
+}
+
+// TagSchema describes the arguments of a custom tag, given either in order,
+// as in @cache(5m, true), or by name, as in @cache(ttl=5m, shared=true).
+type TagSchema struct {
+	Args []TagArg
+}
+
+func (s TagSchema) Validate() error {
+	names := map[string]bool{}
+	for _, arg := range s.Args {
+		if !isValidTagName(arg.Name) {
+			return fmt.Errorf("argument name '%s' is invalid", arg.Name)
+		}
+		if names[strs.ToLower(arg.Name)] {
+			return fmt.Errorf("argument '%s' is declared more than once", arg.Name)
+		}
+		names[strs.ToLower(arg.Name)] = true
+		switch arg.Type {
+		case TagArgString, TagArgInt, TagArgBool, TagArgDuration, TagArgList:
+		case TagArgEnum:
+			if len(arg.Values) == 0 {
+				return fmt.Errorf("enum argument '%s' has no values", arg.Name)
+			}
+		default:
+			return fmt.Errorf("argument '%s' has an invalid type '%s'", arg.Name, arg.Type)
+		}
+	}
+	return nil
+}
+
+func (s TagSchema) Parse(options types.TagOptions, tag string) error {
+	var values []string
+	if tag != "" {
+		values = strings.SplitS(tag, ",")
+	}
+	named := false
+	for i, value := range values {
+		value = strs.TrimSpace(value)
+		arg, v, ok := s.named(value)
+		if ok {
+			named = true
+		} else {
+			if named {
+				return fmt.Errorf("positional value '%s' given after named ones", value)
+			}
+			if i >= len(s.Args) {
+				return fmt.Errorf("too many values, expected at most %d", len(s.Args))
+			}
+			arg, v = s.Args[i], value
+		}
+		if _, ok := options[arg.Name]; ok {
+			return fmt.Errorf("argument '%s' is given more than once", arg.Name)
+		}
+		parsed, err := arg.parse(v)
+		if err != nil {
+			return fmt.Errorf("argument '%s': %v", arg.Name, err)
+		}
+		options[arg.Name] = parsed
+	}
+	for _, arg := range s.Args {
+		if _, ok := options[arg.Name]; !ok && arg.Required {
+			return fmt.Errorf("missing required argument '%s'", arg.Name)
+		}
+	}
+	return nil
+}
+
+func (s TagSchema) named(value string) (TagArg, string, bool) {
+	i := strs.Index(value, "=")
+	if i == -1 {
+		return TagArg{}, "", false
+	}
+	name := strs.TrimSpace(value[:i])
+	for _, arg := range s.Args {
+		if strs.EqualFold(arg.Name, name) {
+			return arg, strs.TrimSpace(value[i+1:]), true
+		}
+	}
+	return TagArg{}, "", false
+}
+
+func (a TagArg) parse(value string) (interface{}, error) {
+	switch a.Type {
+	case TagArgInt:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int '%s'", value)
+		}
+		return v, nil
+	case TagArgBool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool '%s'", value)
+		}
+		return v, nil
+	case TagArgDuration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration '%s'", value)
+		}
+		return v, nil
+	case TagArgList:
+		if strs.HasPrefix(value, "[") && strs.HasSuffix(value, "]") {
+			value = value[1 : len(value)-1]
+		}
+		list := []string{}
+		for _, item := range strings.SplitS(value, ",") {
+			list = append(list, unquote(strs.TrimSpace(item)))
+		}
+		return list, nil
+	case TagArgEnum:
+		value = unquote(value)
+		for _, v := range a.Values {
+			if v == value {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid value '%s', expected one of %s", value, strs.Join(a.Values, ", "))
+	}
+	return unquote(value), nil
+}
+
+func unquote(s string) string {
+	if v, err := strconv.Unquote(s); err == nil {
+		return v
+	}
+	return s
+}
+
+func (p *Parser) RegisterServiceTagSchema(name string, schema TagSchema) error {
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema of service tag '%s': %v", name, err)
+	}
+	err := p.RegisterServiceTagParser(name, func(service types.Service, options types.TagOptions, tag string) error {
+		return schema.Parse(options, tag)
+	})
+	if err == nil {
+		p.tagSchemas["service:"+strs.ToLower(name)] = schema
+	}
+	return err
+}
+
+func (p *Parser) RegisterMethodTagSchema(name string, schema TagSchema) error {
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema of method tag '%s': %v", name, err)
+	}
+	err := p.RegisterMethodTagParser(name, func(method types.Method, options types.TagOptions, tag string) error {
+		return schema.Parse(options, tag)
+	})
+	if err == nil {
+		p.tagSchemas["method:"+strs.ToLower(name)] = schema
+	}
+	return err
+}
+
+func (p *Parser) RegisterParamTagSchema(name string, schema TagSchema) error {
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema of param tag '%s': %v", name, err)
+	}
+	err := p.RegisterParamTagParser(name, func(arg types.Argument, options types.TagOptions, tag string) error {
+		return schema.Parse(options, tag)
+	})
+	if err == nil {
+		p.tagSchemas["param:"+strs.ToLower(name)] = schema
+	}
+	return err
+}
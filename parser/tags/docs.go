@@ -0,0 +1,51 @@
+package tags
+
+import (
+	"github.com/wlMalk/goms/constants"
+)
+
+var (
+	HTTPMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	HTTPOrigins = []string{"BODY", "HEADER", "QUERY", "PATH"}
+	HTTPStreams = []string{"sse", "ws"}
+	Transports  = []string{"HTTP", "GRPC", "JSONRPC", "MQ"}
+	Metrics     = []string{"frequency", "latency", "counter"}
+	Validators  = []string{
+		constants.ValidatorRequired,
+		constants.ValidatorNonEmpty,
+		constants.ValidatorMinLen,
+		constants.ValidatorMaxLen,
+		constants.ValidatorRange,
+		constants.ValidatorRegex,
+		constants.ValidatorEnum,
+	}
+)
+
+const (
+	ServiceNameTagDoc          = "`@name(name)` sets the name the service is exposed with."
+	ServiceTransportsTagDoc    = "`@transports(HTTP, GRPC, JSONRPC, MQ)` limits the transports generated for the service."
+	ServiceMetricsTagDoc       = "`@metrics(frequency, latency, counter)` limits the metrics collected for the service."
+	ServiceHTTPUriPrefixTagDoc = "`@http-URI-prefix(prefix)` is prepended to the HTTP URIs of all the methods."
+	ServiceGenerateTagDoc      = "`@generate(flags...)` enables generate flags for the service and its methods."
+	ServiceGenerateAllTagDoc   = "`@generate-all(flags...)` enables all the generate flags but the given ones, leaving out `open-api` and the JSON-RPC and message queue ones unless they are enabled explicitly."
+
+	MethodNameTagDoc       = "`@name(name)` sets the name the method is exposed with."
+	MethodTransportsTagDoc = "`@transports(HTTP, GRPC, JSONRPC, MQ)` limits the transports generated for the method."
+	MethodMetricsTagDoc    = "`@metrics(frequency, latency, counter)` limits the metrics collected for the method."
+	MethodHTTPMethodTagDoc = "`@http-method(GET)` sets the HTTP method, POST by default. Arguments of methods other than POST, PUT and PATCH cannot come from the body."
+	MethodHTTPUriTagDoc    = "`@http-URI(path/:arg)` sets the HTTP URI of the method, relative to the versioned service prefix."
+	MethodHTTPAbsUriTagDoc = "`@http-abs-URI(/path/:arg)` sets the absolute HTTP URI of the method."
+	MethodHTTPStreamTagDoc = "`@http-stream(sse|ws)` exposes a method returning a stream over HTTP as Server-Sent Events or as a WebSocket."
+	MethodLogsIgnoreTagDoc = "`@logs-ignore(names...)` leaves the given arguments, results or `err` out of the logs."
+	MethodLogsLenTagDoc    = "`@logs-len(names...)` logs the length of the given slice, map or bytes arguments and results instead of their value."
+	MethodAliasTagDoc      = "`@alias(name, alias)` sets the name of an argument or result in the transports."
+	MethodPublishesTagDoc  = "`@publishes(entities...)` publishes the results or arguments of the given entity types as events after a successful call."
+	MethodValidateTagDoc   = "`@validate(argument, rules...)` checks an argument with `required`, `non-empty`, `min-len(n)`, `max-len(n)`, `range(min,max)`, `regex(pattern)` or `enum(values...)`."
+	MethodParamsTagDoc     = "`@params([arguments...], (@param-tags...))` applies param tags, like `@http-origin(QUERY)`, to the given arguments."
+	MethodEnableTagDoc     = "`@enable(flags...)` enables generate flags for the method."
+	MethodDisableTagDoc    = "`@disable(flags...)` disables generate flags for the method."
+	MethodEnableAllTagDoc  = "`@enable-all(flags...)` enables all the generate flags but the given ones for the method, leaving out the JSON-RPC and message queue ones unless the service enables them."
+	MethodDisableAllTagDoc = "`@disable-all(flags...)` disables all the generate flags but the given ones for the method."
+
+	ParamHTTPOriginTagDoc = "`@http-origin(BODY|HEADER|QUERY|PATH)` sets where the argument is read from in HTTP requests, BODY by default."
+)
@@ -16,6 +16,10 @@ func MethodTransportsTag(method *types.Method, tag string) error {
 		constants.MethodGenerateHTTPClientFlag,
 		constants.MethodGenerateGRPCServerFlag,
 		constants.MethodGenerateGRPCClientFlag,
+		constants.MethodGenerateJSONRPCServerFlag,
+		constants.MethodGenerateJSONRPCClientFlag,
+		constants.MethodGenerateMQServerFlag,
+		constants.MethodGenerateMQClientFlag,
 	)
 	for _, i := range transports {
 		switch strs.ToUpper(i) {
@@ -29,6 +33,16 @@ func MethodTransportsTag(method *types.Method, tag string) error {
 				constants.MethodGenerateGRPCServerFlag,
 				constants.MethodGenerateGRPCClientFlag,
 			)
+		case "JSONRPC":
+			method.Generate.Add(
+				constants.MethodGenerateJSONRPCServerFlag,
+				constants.MethodGenerateJSONRPCClientFlag,
+			)
+		case "MQ":
+			method.Generate.Add(
+				constants.MethodGenerateMQServerFlag,
+				constants.MethodGenerateMQClientFlag,
+			)
 		default:
 			return fmt.Errorf("invalid value '%s' for transports method tag in '%s' method", i, method.Name)
 		}
@@ -60,13 +74,7 @@ func MethodMetricsTag(method *types.Method, tag string) error {
 
 func MethodHTTPMethodTag(method *types.Method, tag string) error {
 	httpMethod := strs.ToUpper(tag)
-	if httpMethod != "POST" &&
-		httpMethod != "PATCH" &&
-		httpMethod != "GET" &&
-		httpMethod != "PUT" &&
-		httpMethod != "DELETE" &&
-		httpMethod != "OPTIONS" &&
-		httpMethod != "HEAD" {
+	if !contains(HTTPMethods, httpMethod) {
 		return fmt.Errorf("invalid http-method value '%s'", tag)
 	}
 	method.Options.HTTP.Method = httpMethod
@@ -83,6 +91,35 @@ func MethodHTTPAbsUriTag(method *types.Method, tag string) error {
 	return nil
 }
 
+func MethodHTTPStreamTag(method *types.Method, tag string) error {
+	stream := strs.ToLower(strs.TrimSpace(tag))
+	if !contains(HTTPStreams, stream) {
+		return fmt.Errorf("invalid http-stream value '%s'", tag)
+	}
+	if !method.IsServerStreaming() || method.IsClientStreaming() {
+		return fmt.Errorf("invalid http-stream tag in '%s' method: only methods returning a stream and not taking one can be streamed over HTTP", method.Name)
+	}
+	method.Options.HTTP.Stream = stream
+	return nil
+}
+
+func MethodPublishesTag(method *types.Method, tag string) error {
+	if method.IsStreaming() {
+		return fmt.Errorf("invalid publishes tag in '%s' method: streaming methods cannot publish events", method.Name)
+	}
+	entities := strings.SplitS(tag, ",")
+	if len(entities) == 0 {
+		return fmt.Errorf("invalid publishes tag in '%s' method: no entity was given", method.Name)
+	}
+	for _, e := range entities {
+		entity := strs.TrimSpace(e)
+		if !contains(method.Options.Events.Publishes, entity) {
+			method.Options.Events.Publishes = append(method.Options.Events.Publishes, entity)
+		}
+	}
+	return nil
+}
+
 func MethodLogsIgnoreTag(method *types.Method, tag string) error {
 	params := strings.SplitS(tag, ",")
 paramsLoop:
@@ -169,5 +206,23 @@ func MethodNameTag(method *types.Method, tag string) error {
 }
 
 func MethodValidateTag(method *types.Method, tag string) error {
-	return nil
+	params := strings.SplitS(tag, ",")
+	if len(params) < 2 || strs.TrimSpace(params[0]) == "" {
+		return fmt.Errorf("invalid params '%s' for validate tag in '%s' method", tag, method.Name)
+	}
+	for _, arg := range method.Arguments {
+		if strings.ToUpperFirst(arg.Name) != strings.ToUpperFirst(params[0]) &&
+			(len(arg.Alias) == 0 || strs.ToLower(arg.Alias) != strs.ToLower(params[0])) {
+			continue
+		}
+		for _, p := range params[1:] {
+			validator, err := parseValidator(arg, p)
+			if err != nil {
+				return fmt.Errorf("invalid validator '%s' for '%s' argument in '%s' method: %v", p, arg.Name, method.Name, err)
+			}
+			arg.Options.Validators = append(arg.Options.Validators, validator)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid name '%s' for validate tag in '%s' method", params[0], method.Name)
 }
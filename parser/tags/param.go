@@ -9,7 +9,7 @@ import (
 
 func ParamHTTPOriginTag(arg *types.Argument, tag string) error {
 	origin := strs.ToUpper(tag)
-	if origin != "BODY" && origin != "HEADER" && origin != "QUERY" && origin != "PATH" {
+	if !contains(HTTPOrigins, origin) {
 		return fmt.Errorf("invalid http-origin value '%s'", tag)
 	}
 	arg.Options.HTTP.Origin = origin
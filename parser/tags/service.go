@@ -16,6 +16,10 @@ func ServiceTransportsTag(service *types.Service, tag string) error {
 		constants.ServiceGenerateHTTPClientFlag,
 		constants.ServiceGenerateGRPCServerFlag,
 		constants.ServiceGenerateGRPCClientFlag,
+		constants.ServiceGenerateJSONRPCServerFlag,
+		constants.ServiceGenerateJSONRPCClientFlag,
+		constants.ServiceGenerateMQServerFlag,
+		constants.ServiceGenerateMQClientFlag,
 	)
 	for _, i := range transports {
 		switch strs.ToUpper(i) {
@@ -29,6 +33,16 @@ func ServiceTransportsTag(service *types.Service, tag string) error {
 				constants.ServiceGenerateGRPCServerFlag,
 				constants.ServiceGenerateGRPCClientFlag,
 			)
+		case "JSONRPC":
+			service.Generate.Add(
+				constants.ServiceGenerateJSONRPCServerFlag,
+				constants.ServiceGenerateJSONRPCClientFlag,
+			)
+		case "MQ":
+			service.Generate.Add(
+				constants.ServiceGenerateMQServerFlag,
+				constants.ServiceGenerateMQClientFlag,
+			)
 		default:
 			return fmt.Errorf("invalid value '%s' for transports service tag in '%s' service", i, service.Name)
 		}
@@ -0,0 +1,140 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	strs "strings"
+
+	"github.com/wlMalk/goms/constants"
+	"github.com/wlMalk/goms/generator/strings"
+	"github.com/wlMalk/goms/parser/types"
+)
+
+func parseValidator(arg *types.Argument, s string) (validator types.Validator, err error) {
+	s = strs.TrimSpace(s)
+	name, params := s, ""
+	if i := strs.Index(s, "("); i > 0 && strs.HasSuffix(s, ")") {
+		name, params = strs.TrimSpace(s[:i]), strs.TrimSpace(s[i+1:len(s)-1])
+	}
+	validator.Name = strs.ToLower(name)
+	if validator.Name == constants.ValidatorRegex {
+		validator.Args = []string{params}
+	} else if params != "" {
+		validator.Args = strings.SplitS(params, ",")
+	}
+	t := arg.Type
+	switch validator.Name {
+	case constants.ValidatorRequired:
+		if !t.IsPointer && !isLenType(t) {
+			return validator, errors.New("argument can not be empty")
+		}
+		return validator, argsCount(validator, 0)
+	case constants.ValidatorNonEmpty:
+		if !isLenType(t) {
+			return validator, errors.New("argument has no length")
+		}
+		return validator, argsCount(validator, 0)
+	case constants.ValidatorMinLen, constants.ValidatorMaxLen:
+		if !isLenType(t) {
+			return validator, errors.New("argument has no length")
+		}
+		if err := argsCount(validator, 1); err != nil {
+			return validator, err
+		}
+		if n, err := strconv.Atoi(validator.Args[0]); err != nil || n < 0 {
+			return validator, fmt.Errorf("invalid length '%s'", validator.Args[0])
+		}
+	case constants.ValidatorRange:
+		if !isNumberType(t) {
+			return validator, errors.New("argument is not a number")
+		}
+		if err := argsCount(validator, 2); err != nil {
+			return validator, err
+		}
+		var bounds [2]float64
+		for i, a := range validator.Args {
+			bounds[i], err = parseNumber(t, a)
+			if err != nil {
+				return validator, err
+			}
+		}
+		if bounds[0] > bounds[1] {
+			return validator, fmt.Errorf("min '%s' is greater than max '%s'", validator.Args[0], validator.Args[1])
+		}
+	case constants.ValidatorRegex:
+		if !isStringType(t) {
+			return validator, errors.New("argument is not a string")
+		}
+		if _, err := regexp.Compile(params); err != nil {
+			return validator, err
+		}
+	case constants.ValidatorEnum:
+		if len(validator.Args) == 0 {
+			if t.IsBuiltin || t.IsSlice || t.IsVariadic || t.IsMap {
+				return validator, errors.New("values are required for arguments which are not enums")
+			}
+			return validator, nil
+		}
+		switch {
+		case isStringType(t):
+			for i, a := range validator.Args {
+				if v, err := strconv.Unquote(a); err == nil {
+					validator.Args[i] = v
+				}
+			}
+		case isNumberType(t):
+			for _, a := range validator.Args {
+				if _, err := parseNumber(t, a); err != nil {
+					return validator, err
+				}
+			}
+		default:
+			return validator, errors.New("argument is neither a string nor a number")
+		}
+	default:
+		return validator, fmt.Errorf("unknown validator '%s'", name)
+	}
+	return validator, nil
+}
+
+func argsCount(validator types.Validator, n int) error {
+	if len(validator.Args) != n {
+		return fmt.Errorf("'%s' validator takes %d params", validator.Name, n)
+	}
+	return nil
+}
+
+func parseNumber(t *types.Type, s string) (float64, error) {
+	if strs.HasPrefix(t.Name, "float") {
+		return strconv.ParseFloat(s, 64)
+	}
+	if strs.HasPrefix(t.Name, "uint") || t.Name == "byte" {
+		n, err := strconv.ParseUint(s, 10, 64)
+		return float64(n), err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return float64(n), err
+}
+
+func isLenType(t *types.Type) bool {
+	return t.IsSlice || t.IsVariadic || t.IsMap || t.IsBytes || isStringType(t)
+}
+
+func isStringType(t *types.Type) bool {
+	return t.IsBuiltin && !t.IsSlice && !t.IsVariadic && !t.IsMap && !t.IsBytes && t.Name == "string"
+}
+
+func isNumberType(t *types.Type) bool {
+	if !t.IsBuiltin || t.IsSlice || t.IsVariadic || t.IsMap || t.IsBytes {
+		return false
+	}
+	switch t.Name {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "byte", "rune":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wlMalk/goms/parser/types"
+)
+
+var (
+	stringType = &types.Type{Name: "string", IsBuiltin: true}
+	intType    = &types.Type{Name: "int", IsBuiltin: true}
+	uintType   = &types.Type{Name: "uint", IsBuiltin: true}
+	floatType  = &types.Type{Name: "float64", IsBuiltin: true}
+	sliceType  = &types.Type{Name: "string", IsBuiltin: true, IsSlice: true}
+	mapType    = &types.Type{Name: "string", IsMap: true, Value: intType}
+	bytesType  = &types.Type{Name: "byte", IsBytes: true}
+	ptrType    = &types.Type{Name: "User", IsPointer: true, IsEntity: true}
+	enumType   = &types.Type{Name: "Role", IsEnum: true}
+	entityType = &types.Type{Name: "User", IsEntity: true}
+)
+
+func TestParseValidator(t *testing.T) {
+	tests := []struct {
+		typ     *types.Type
+		tag     string
+		name    string
+		args    []string
+		invalid bool
+	}{
+		{typ: stringType, tag: "required", name: "required"},
+		{typ: ptrType, tag: "required", name: "required"},
+		{typ: sliceType, tag: "Required", name: "required"},
+		{typ: mapType, tag: "required", name: "required"},
+		{typ: intType, tag: "required", invalid: true},
+		{typ: entityType, tag: "required", invalid: true},
+		{typ: stringType, tag: "required(1)", invalid: true},
+
+		{typ: stringType, tag: "non-empty", name: "non-empty"},
+		{typ: bytesType, tag: "non-empty", name: "non-empty"},
+		{typ: ptrType, tag: "non-empty", invalid: true},
+		{typ: stringType, tag: "non-empty(a)", invalid: true},
+
+		{typ: stringType, tag: "min-len(3)", name: "min-len", args: []string{"3"}},
+		{typ: sliceType, tag: "max-len(12)", name: "max-len", args: []string{"12"}},
+		{typ: mapType, tag: "max-len( 0 )", name: "max-len", args: []string{"0"}},
+		{typ: stringType, tag: "min-len", invalid: true},
+		{typ: stringType, tag: "min-len()", invalid: true},
+		{typ: stringType, tag: "min-len(-1)", invalid: true},
+		{typ: stringType, tag: "min-len(a)", invalid: true},
+		{typ: stringType, tag: "min-len(1,2)", invalid: true},
+		{typ: intType, tag: "max-len(3)", invalid: true},
+
+		{typ: intType, tag: "range(0,150)", name: "range", args: []string{"0", "150"}},
+		{typ: intType, tag: "range(-5, 5)", name: "range", args: []string{"-5", "5"}},
+		{typ: floatType, tag: "range(0.5,1.5)", name: "range", args: []string{"0.5", "1.5"}},
+		{typ: intType, tag: "range(5,5)", name: "range", args: []string{"5", "5"}},
+		{typ: intType, tag: "range(6,5)", invalid: true},
+		{typ: intType, tag: "range(1)", invalid: true},
+		{typ: intType, tag: "range(0.5,1)", invalid: true},
+		{typ: uintType, tag: "range(-1,1)", invalid: true},
+		{typ: stringType, tag: "range(0,1)", invalid: true},
+
+		{typ: stringType, tag: "regex(^[a-z]+$)", name: "regex", args: []string{"^[a-z]+$"}},
+		{typ: stringType, tag: "regex(^a,b$)", name: "regex", args: []string{"^a,b$"}},
+		{typ: stringType, tag: "regex(a)", name: "regex", args: []string{"a"}},
+		{typ: stringType, tag: "regex([a-)", invalid: true},
+		{typ: intType, tag: "regex(a)", invalid: true},
+
+		{typ: stringType, tag: "enum(admin, user)", name: "enum", args: []string{"admin", "user"}},
+		{typ: stringType, tag: `enum("a b", c)`, name: "enum", args: []string{"a b", "c"}},
+		{typ: stringType, tag: "enum(a)", name: "enum", args: []string{"a"}},
+		{typ: intType, tag: "enum(1,2,3)", name: "enum", args: []string{"1", "2", "3"}},
+		{typ: intType, tag: "enum(1)", name: "enum", args: []string{"1"}},
+		{typ: enumType, tag: "enum", name: "enum"},
+		{typ: intType, tag: "enum(a)", invalid: true},
+		{typ: stringType, tag: "enum", invalid: true},
+		{typ: entityType, tag: "enum(a)", invalid: true},
+
+		{typ: stringType, tag: "unknown", invalid: true},
+	}
+	for _, test := range tests {
+		arg := &types.Argument{Name: "arg", Type: test.typ}
+		v, err := parseValidator(arg, test.tag)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("%s on %s: expected an error", test.tag, test.typ.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s on %s: unexpected error: %v", test.tag, test.typ.String(), err)
+			continue
+		}
+		if v.Name != test.name || !reflect.DeepEqual(v.Args, test.args) {
+			t.Errorf("%s on %s: got %s %q, want %s %q", test.tag, test.typ.String(), v.Name, v.Args, test.name, test.args)
+		}
+	}
+}
+
+func TestMethodValidateTag(t *testing.T) {
+	method := &types.Method{
+		Name: "CreateUser",
+		Arguments: []*types.Argument{
+			{Name: "name", Type: stringType},
+			{Name: "age", Alias: "years", Type: intType},
+		},
+	}
+	if err := MethodValidateTag(method, "name, required, min-len(3)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := MethodValidateTag(method, "years, range(0,150)"); err != nil {
+		t.Fatal(err)
+	}
+	if got := method.Arguments[0].Options.Validators; len(got) != 2 || got[0].Name != "required" || got[1].Name != "min-len" {
+		t.Errorf("unexpected validators for name: %v", got)
+	}
+	if got := method.Arguments[1].Options.Validators; len(got) != 1 || got[0].Name != "range" {
+		t.Errorf("unexpected validators for age: %v", got)
+	}
+	if err := MethodValidateTag(method, "name, regex(^[^)]+$), regex(^(a|[(])$)"); err != nil {
+		t.Fatal(err)
+	}
+	if got := method.Arguments[0].Options.Validators; len(got) != 4 || !reflect.DeepEqual(got[2].Args, []string{"^[^)]+$"}) || !reflect.DeepEqual(got[3].Args, []string{"^(a|[(])$"}) {
+		t.Errorf("unexpected validators for name: %v", got)
+	}
+	for _, tag := range []string{"name", "", "missing, required", "age, min-len(1)", "name, regex(^a(b$)", "name, regex(^a)b$)"} {
+		if err := MethodValidateTag(method, tag); err == nil {
+			t.Errorf("%s: expected an error", tag)
+		}
+	}
+}
@@ -141,6 +141,14 @@ func parseType(typ astTypes.Type) (*types.Type, error) {
 		if !parseTImportType(t, Ttyp) {
 			return nil, err
 		}
+	case astTypes.TChan:
+		if Ttyp.Direction != astTypes.ChanDirRecv {
+			return nil, fmt.Errorf("invalid type %s, streams have to be receive-only channels", typ.String())
+		}
+		t.IsStream = true
+		if !parseRepeatedValueType(t, Ttyp.Next) {
+			return nil, err
+		}
 	default:
 		return nil, err
 	}
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"fmt"
 	strs "strings"
+	"time"
 )
 
 type GenerateList []string
@@ -69,47 +71,121 @@ func (g *GenerateList) Empty() {
 
 type TagOptions map[string]interface{}
 
+func (o TagOptions) Has(name string) bool {
+	_, ok := o[name]
+	return ok
+}
+
+func (o TagOptions) String(name string) string {
+	switch v := o[name].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (o TagOptions) Int(name string) int {
+	switch v := o[name].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func (o TagOptions) Bool(name string) bool {
+	v, _ := o[name].(bool)
+	return v
+}
+
+func (o TagOptions) Duration(name string) time.Duration {
+	switch v := o[name].(type) {
+	case time.Duration:
+		return v
+	case float64:
+		return time.Duration(v)
+	case string:
+		d, _ := time.ParseDuration(v)
+		return d
+	}
+	return 0
+}
+
+func (o TagOptions) List(name string) []string {
+	switch v := o[name].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	}
+	return nil
+}
+
 type TagsOptions map[string]TagOptions
 
+func (o TagsOptions) Has(tag string) bool {
+	_, ok := o[strs.ToLower(tag)]
+	return ok
+}
+
+func (o TagsOptions) Get(tag string) TagOptions {
+	return o[strs.ToLower(tag)]
+}
+
 type ServiceOptions struct {
-	HTTP HTTPServiceOptions
-	GRPC GRPCServiceOptions
+	HTTP HTTPServiceOptions `json:"http"`
+	GRPC GRPCServiceOptions `json:"grpc"`
 }
 
 type HTTPServiceOptions struct {
-	URIPrefix string
+	URIPrefix string `json:"uriPrefix"`
 }
 
 type GRPCServiceOptions struct {
 }
 
 type MethodOptions struct {
-	HTTP    HTTPMethodOptions
-	GRPC    GRPCMethodOptions
-	Logging LoggingMethodOptions
+	HTTP    HTTPMethodOptions    `json:"http"`
+	GRPC    GRPCMethodOptions    `json:"grpc"`
+	Logging LoggingMethodOptions `json:"logging"`
+	Events  EventsMethodOptions  `json:"events"`
 }
 
 type HTTPMethodOptions struct {
-	Method string
-	URI    string
-	AbsURI string
+	Method string `json:"method"`
+	URI    string `json:"uri"`
+	AbsURI string `json:"absURI"`
+	Stream string `json:"stream,omitempty"`
 }
 
 type GRPCMethodOptions struct {
 }
 
 type LoggingMethodOptions struct {
-	IgnoredArguments []string
-	IgnoredResults   []string
-	LenArguments     []string
-	LenResults       []string
-	IgnoreError      bool
+	IgnoredArguments []string `json:"ignoredArguments,omitempty"`
+	IgnoredResults   []string `json:"ignoredResults,omitempty"`
+	LenArguments     []string `json:"lenArguments,omitempty"`
+	LenResults       []string `json:"lenResults,omitempty"`
+	IgnoreError      bool     `json:"ignoreError,omitempty"`
+}
+
+type EventsMethodOptions struct {
+	Publishes []string `json:"publishes,omitempty"`
 }
 
 type ArgumentOptions struct {
-	HTTP HTTPArgumentOptions
+	HTTP       HTTPArgumentOptions `json:"http"`
+	Validators []Validator         `json:"validators,omitempty"`
 }
 
 type HTTPArgumentOptions struct {
-	Origin string
+	Origin string `json:"origin"`
 }
@@ -1,24 +1,26 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 )
 
 type Service struct {
-	Name            string
-	Alias           string
-	Docs            []string
-	Path            string
-	ImportPath      string
-	Version         Version
-	Methods         []Method
-	Entities        []Entity
-	ArgumentsGroups []ArgumentsGroup
-	Enums           []Enum
-
-	Options      ServiceOptions
-	OtherOptions TagsOptions
-	Generate     GenerateList
+	Name            string           `json:"name"`
+	Alias           string           `json:"alias,omitempty"`
+	Docs            []string         `json:"docs,omitempty"`
+	Path            string           `json:"path,omitempty"`
+	ImportPath      string           `json:"importPath,omitempty"`
+	Version         Version          `json:"version"`
+	Methods         []Method         `json:"methods,omitempty"`
+	Entities        []Entity         `json:"entities,omitempty"`
+	ArgumentsGroups []ArgumentsGroup `json:"argumentsGroups,omitempty"`
+	Enums           []Enum           `json:"enums,omitempty"`
+
+	Options      ServiceOptions `json:"options"`
+	OtherOptions TagsOptions    `json:"otherOptions,omitempty"`
+	Generate     GenerateList   `json:"generate,omitempty"`
 }
 
 type Version struct {
@@ -27,10 +29,23 @@ type Version struct {
 	Patch int
 }
 
-func (v *Version) MarshalJSON() ([]byte, error) {
+func (v Version) MarshalJSON() ([]byte, error) {
 	return []byte("\"" + v.String() + "\""), nil
 }
 
+func (v *Version) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*v = Version{}
+	_, err := fmt.Sscanf(s, "%d.%d.%d", &v.Major, &v.Minor, &v.Patch)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("invalid version '%s'", s)
+	}
+	return nil
+}
+
 func (v *Version) String() string {
 	return v.StringSpecial(".")
 }
@@ -54,42 +69,66 @@ func (v *Version) FullStringSpecial(sep string) string {
 }
 
 type Method struct {
-	Name      string
-	Alias     string
-	Docs      []string
-	Arguments []*Argument
-	Results   []*Field
+	Name      string      `json:"name"`
+	Alias     string      `json:"alias,omitempty"`
+	Docs      []string    `json:"docs,omitempty"`
+	Arguments []*Argument `json:"arguments,omitempty"`
+	Results   []*Field    `json:"results,omitempty"`
+
+	Options      MethodOptions `json:"options"`
+	OtherOptions TagsOptions   `json:"otherOptions,omitempty"`
+	Generate     GenerateList  `json:"generate,omitempty"`
+}
+
+// IsClientStreaming reports whether the method takes a stream of values, as
+// its only argument of a <-chan type.
+func (m Method) IsClientStreaming() bool {
+	return len(m.Arguments) == 1 && m.Arguments[0].Type.IsStream
+}
 
-	Options      MethodOptions
-	OtherOptions TagsOptions
-	Generate     GenerateList
+// IsServerStreaming reports whether the method returns a stream of values, as
+// its only result of a <-chan type.
+func (m Method) IsServerStreaming() bool {
+	return len(m.Results) == 1 && m.Results[0].Type.IsStream
+}
+
+func (m Method) IsStreaming() bool {
+	return m.IsClientStreaming() || m.IsServerStreaming()
 }
 
 type Type struct {
-	PkgImportPath    string
-	Pkg              string
-	Name             string
-	IsPointer        bool
-	IsSlice          bool
-	IsVariadic       bool
-	IsMap            bool
-	IsImport         bool
-	IsEntity         bool
-	IsEnum           bool
-	IsBuiltin        bool
-	IsArgumentsGroup bool
-	IsBytes          bool
-	Value            *Type
-	Entity           *Entity
-	Enum             *Enum
-	ArgumentsGroup   *ArgumentsGroup
+	PkgImportPath    string          `json:"pkgImportPath,omitempty"`
+	Pkg              string          `json:"pkg,omitempty"`
+	Name             string          `json:"name"`
+	IsPointer        bool            `json:"isPointer,omitempty"`
+	IsSlice          bool            `json:"isSlice,omitempty"`
+	IsVariadic       bool            `json:"isVariadic,omitempty"`
+	IsMap            bool            `json:"isMap,omitempty"`
+	IsImport         bool            `json:"isImport,omitempty"`
+	IsEntity         bool            `json:"isEntity,omitempty"`
+	IsEnum           bool            `json:"isEnum,omitempty"`
+	IsBuiltin        bool            `json:"isBuiltin,omitempty"`
+	IsArgumentsGroup bool            `json:"isArgumentsGroup,omitempty"`
+	IsBytes          bool            `json:"isBytes,omitempty"`
+	IsStream         bool            `json:"isStream,omitempty"`
+	Value            *Type           `json:"value,omitempty"`
+	Entity           *Entity         `json:"-"`
+	Enum             *Enum           `json:"-"`
+	ArgumentsGroup   *ArgumentsGroup `json:"-"`
 }
 
 func (t *Type) MarshalJSON() ([]byte, error) {
-	return []byte("\"" + t.String() + "\""), nil
+	type jsonType Type
+	return json.Marshal(struct {
+		*jsonType
+		GoType string `json:"goType"`
+	}{(*jsonType)(t), t.String()})
 }
 
 func (t *Type) String() (s string) {
+	if t.IsStream {
+		s += "<-chan "
+	}
 	if t.IsVariadic {
 		s += "..."
 	}
@@ -126,6 +165,22 @@ func (t *Type) NameWithImport() string {
 	return t.Name
 }
 
+// Elem returns the type of the values of a stream, or the type itself.
+func (t *Type) Elem() *Type {
+	if !t.IsStream {
+		return t
+	}
+	elem := *t
+	elem.IsStream = false
+	return &elem
+}
+
+// IsPublishable reports whether the type is a single value, or a pointer to
+// one, of the given entity declared in the service.
+func (t *Type) IsPublishable(entity string) bool {
+	return t.Name == entity && t.IsEntity && !t.IsImport && !t.IsSlice && !t.IsVariadic && !t.IsMap && !t.IsStream
+}
+
 func (t *Type) GoType() string {
 	if t.IsVariadic {
 		return "[]" + t.NameWithImport()
@@ -163,54 +218,57 @@ func toProtoBufType(name string) string {
 }
 
 type ArgumentsGroup struct {
-	Name      string
-	Docs      []string
-	Arguments []*Argument
+	Name      string      `json:"name"`
+	Docs      []string    `json:"docs,omitempty"`
+	Arguments []*Argument `json:"arguments,omitempty"`
 }
 
 type Entity struct {
-	Name   string
-	Docs   []string
-	Fields []*Field
+	Name          string   `json:"name"`
+	Docs          []string `json:"docs,omitempty"`
+	Fields        []*Field `json:"fields,omitempty"`
+	IsImport      bool     `json:"isImport,omitempty"`
+	Pkg           string   `json:"pkg,omitempty"`
+	PkgImportPath string   `json:"pkgImportPath,omitempty"`
 }
 
 type EnumCase struct {
-	Name  string
-	Value int
+	Name  string `json:"name"`
+	Value int    `json:"value"`
 }
 
 type Enum struct {
-	Name  string
-	Docs  []string
-	Cases []EnumCase
+	Name  string     `json:"name"`
+	Docs  []string   `json:"docs,omitempty"`
+	Cases []EnumCase `json:"cases,omitempty"`
 }
 
 type Argument struct {
-	Name         string
-	Docs         []string
-	Alias        string
-	Type         *Type
-	IsOptional   bool
-	DefaultValue string
+	Name         string   `json:"name"`
+	Docs         []string `json:"docs,omitempty"`
+	Alias        string   `json:"alias,omitempty"`
+	Type         *Type    `json:"type"`
+	IsOptional   bool     `json:"isOptional"`
+	DefaultValue string   `json:"defaultValue,omitempty"`
 
-	Options      ArgumentOptions
-	OtherOptions TagsOptions
+	Options      ArgumentOptions `json:"options"`
+	OtherOptions TagsOptions     `json:"otherOptions,omitempty"`
 }
 
 type Field struct {
-	Name  string
-	Docs  []string
-	Alias string
-	Type  *Type
-	Tags  map[string][]string
+	Name  string              `json:"name"`
+	Docs  []string            `json:"docs,omitempty"`
+	Alias string              `json:"alias,omitempty"`
+	Type  *Type               `json:"type"`
+	Tags  map[string][]string `json:"tags,omitempty"`
 }
 
 type Validator struct {
-	Name string
-	Args []string
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
 }
 
 type Middleware struct {
-	Name string
-	Args []string
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
 }
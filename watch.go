@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	strs "strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/wlMalk/goms/config"
+	"github.com/wlMalk/goms/generator"
+)
+
+func watchFlags(fs *flag.FlagSet) func() error {
+	var input inputFlags
+	var specs specsFlags
+	var out string
+	var debounce time.Duration
+	input.register(fs)
+	fs.StringVar(&out, "out", "", "root `directory` for the versioned generated code (default is the directory of the service definition)")
+	specs.register(fs)
+	fs.DurationVar(&debounce, "debounce", 200*time.Millisecond, "`duration` to wait for more changes before regenerating")
+	return func() error {
+		banner()
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return err
+		}
+		defer watcher.Close()
+		w := &watchSet{input: &input, watcher: watcher}
+		if err := w.update(); err != nil {
+			return err
+		}
+		regenerate(&input, &specs, out)
+		fmt.Println("Watching for changes, press Ctrl+C to stop")
+		var timer <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return nil
+				}
+				if w.relevant(event.Name) {
+					timer = time.After(debounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return nil
+				}
+				warn(err.Error())
+			case <-timer:
+				timer = nil
+				if err := w.update(); err != nil {
+					report(err)
+					continue
+				}
+				regenerate(&input, &specs, out)
+			}
+		}
+	}
+}
+
+// regenerate runs generate once, writing only the stale files and reporting
+// errors instead of returning them so that watching goes on.
+func regenerate(input *inputFlags, specs *specsFlags, out string) {
+	fmt.Printf("\n[%s] ", time.Now().Format("15:04:05"))
+	changes, manifests, err := generate(input, specs, out)
+	if err != nil {
+		fmt.Println()
+		report(err)
+		return
+	}
+	stale := changes.Stale()
+	if len(stale) == 0 {
+		success("All files are up to date")
+		return
+	}
+	if err := stale.Save(); err != nil {
+		fmt.Println()
+		report(err)
+		return
+	}
+	for _, manifest := range manifests {
+		if err := manifest.Save(); err != nil {
+			fmt.Println()
+			report(err)
+			return
+		}
+	}
+	success(fmt.Sprintf("%d files are regenerated", len(stale)))
+	for _, change := range stale {
+		fmt.Printf("%-10s %s\n", change.Action, relativePath(change.Path))
+	}
+}
+
+// watchSet keeps the watcher on the directories holding the service
+// definition, the configuration file and the templates, and tells which
+// changed files should trigger a new generation.
+type watchSet struct {
+	input     *inputFlags
+	watcher   *fsnotify.Watcher
+	files     map[string]bool
+	packages  map[string]bool
+	configs   map[string]bool
+	config    string
+	templates string
+}
+
+func (w *watchSet) update() error {
+	files, err := w.input.files()
+	if err != nil {
+		return err
+	}
+	w.files, w.packages, w.configs = map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, name := range files {
+		w.files[name] = true
+	}
+	for _, name := range w.input.in {
+		if info, err := os.Stat(name); err == nil && info.IsDir() {
+			name, _ = filepath.Abs(name)
+			w.packages[name] = true
+		}
+	}
+	if len(w.input.in) == 0 {
+		name, _ := filepath.Abs(".")
+		w.packages[name] = true
+	}
+	dir := filepath.Dir(files[0])
+	root, err := searchRoot(dir)
+	if err != nil {
+		return err
+	}
+	dirs := map[string]bool{}
+	for _, name := range files {
+		dirs[filepath.Dir(name)] = true
+	}
+	if w.input.config != "" {
+		w.config, _ = filepath.Abs(w.input.config)
+		dirs[filepath.Dir(w.config)] = true
+	} else {
+		for d := dir; ; d = filepath.Dir(d) {
+			w.configs[d] = true
+			dirs[d] = true
+			if d == root || filepath.Dir(d) == d {
+				break
+			}
+		}
+	}
+	w.templates = generator.FindTemplatesDir(dir, root)
+	if w.templates != "" {
+		err := filepath.Walk(w.templates, func(p string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				dirs[p] = true
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for d := range dirs {
+		if err := w.watcher.Add(d); err != nil {
+			return fmt.Errorf("cannot watch '%s': %v", relativePath(d), err)
+		}
+	}
+	return nil
+}
+
+func (w *watchSet) relevant(name string) bool {
+	name, _ = filepath.Abs(name)
+	dir, base := filepath.Split(name)
+	dir = filepath.Clean(dir)
+	if w.files[name] || name == w.config || w.config == "" && base == config.FileName && w.configs[dir] {
+		return true
+	}
+	if w.packages[dir] && strs.HasSuffix(base, ".go") && !strs.HasSuffix(base, "_test.go") {
+		return true
+	}
+	return w.templates != "" && strs.HasPrefix(name, w.templates+string(filepath.Separator))
+}